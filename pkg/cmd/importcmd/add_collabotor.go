@@ -67,6 +67,7 @@ func (o *ImportOptions) AddAndAcceptCollaborator(newRepository bool) error {
 		if err != nil {
 			return errors.Wrapf(err, "failed to add %s as a collaborator to %s", pipelineUserName, fullRepoName)
 		}
+		o.GetReporter().AddedCollaborator(fullRepoName, pipelineUserName)
 
 		if o.OperatorNamespace == "" {
 			o.OperatorNamespace = boot.GitOperatorNamespace
@@ -1,7 +1,6 @@
 package importcmd
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -18,24 +17,10 @@ import (
 	"github.com/pkg/errors"
 )
 
-var (
-	kptFile = `apiVersion: kpt.dev/v1alpha1
-kind: Kptfile
-metadata:
-    name: %s
-upstream:
-    type: git
-    git:
-        commit: %s
-        repo: %s
-        directory: %s
-        ref: master
-`
-)
-
 // createMissingLighthouseKptFiles lets create any missing Kptfile for any .lighthouse/somedir directories
-// so that after the pipeline folder has been added we can later on upgrade it from its source via kpt
-func (o *ImportOptions) createMissingLighthouseKptFiles(lighthouseDir, packName string) error {
+// so that after the pipeline folder has been added we can later on upgrade it from its source via kpt.
+// The catalogRef is the git ref the pipeline catalog was cloned at which defaults to its current branch
+func (o *ImportOptions) createMissingLighthouseKptFiles(lighthouseDir, packName, catalogRef string) error {
 	fileSlice, err := os.ReadDir(lighthouseDir)
 	if err != nil {
 		return errors.Wrapf(err, "failed to read dir %s", lighthouseDir)
@@ -45,69 +30,99 @@ func (o *ImportOptions) createMissingLighthouseKptFiles(lighthouseDir, packName
 			continue
 		}
 		name := f.Name()
-
-		dir := filepath.Join(lighthouseDir, name)
-		triggerFile := filepath.Join(dir, "triggers.yaml")
-		exists, err := files.FileExists(triggerFile)
+		missing, err := o.isMissingKptfile(lighthouseDir, name)
 		if err != nil {
-			return errors.Wrapf(err, "failed to check if file exists %s", triggerFile)
+			return err
 		}
-		if !exists {
+		if !missing {
 			continue
 		}
-
-		// let's check if we have a local Kptfile for this trigger folder
-		localKptDir := filepath.Join(o.Dir, ".lighthouse", name)
-		localKptFile := filepath.Join(localKptDir, "Kptfile")
-		exists, err = files.FileExists(localKptFile)
+		err = o.saveLighthouseKptfile(lighthouseDir, packName, name, filepath.Join(o.Dir, ".lighthouse", name), catalogRef)
 		if err != nil {
-			return errors.Wrapf(err, "failed to check if file exists %s", localKptFile)
-		}
-		if exists {
-			continue
+			return err
 		}
+	}
+	return nil
+}
 
-		hasUses, err := CheckForUsesImage(dir, triggerFile)
-		if err != nil {
-			return errors.Wrapf(err, "failed to check for image: uses:sourceURI")
-		}
-		if hasUses {
-			continue
-		}
+// saveLighthouseKptfile saves a Kptfile in the local pipeline dir which records the pipeline folder of the pack in
+// the pipeline catalog it was copied from
+func (o *ImportOptions) saveLighthouseKptfile(lighthouseDir, packName, name, localKptDir, catalogRef string) error {
+	localKptFile := filepath.Join(localKptDir, KptfileName)
+	sha, err := gitclient.GetLatestCommitSha(o.Git(), lighthouseDir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to discover latest git commit for dir %s", lighthouseDir)
+	}
 
-		sha, err := gitclient.GetLatestCommitSha(o.Git(), lighthouseDir)
-		if err != nil {
-			return errors.Wrapf(err, "failed to discover latest git commit for dir %s", lighthouseDir)
-		}
+	gitURL, err := gitdiscovery.FindGitURLFromDir(lighthouseDir, true)
+	if err != nil {
+		return errors.Wrapf(err, "failed to discover git URL in dir %s", lighthouseDir)
+	}
 
-		gitURL, err := gitdiscovery.FindGitURLFromDir(lighthouseDir, true)
-		if err != nil {
-			return errors.Wrapf(err, "failed to discover git URL in dir %s", lighthouseDir)
-		}
+	// let's remove any user/passwords just in case
+	gitURL = stringhelpers.SanitizeURL(gitURL)
 
-		// let's remove any user/passwords just in case
-		gitURL = stringhelpers.SanitizeURL(gitURL)
+	if gitURL == "" {
+		return errors.Errorf("failed to find git URL in dir %s", lighthouseDir)
+	}
 
-		if gitURL == "" {
-			return errors.Errorf("failed to find git URL in dir %s", lighthouseDir)
-		}
+	err = os.MkdirAll(localKptDir, files.DefaultDirWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create dir %s", localKptDir)
+	}
 
-		err = os.MkdirAll(localKptDir, files.DefaultDirWritePermissions)
-		if err != nil {
-			return errors.Wrapf(err, "failed to create dir %s", localKptDir)
-		}
+	fromDir := filepath.Join("/packs", packName, ".lighthouse", name) //nolint:gocritic
+	gitURL = strings.TrimSuffix(gitURL, ".git")
+	ref := o.pipelineCatalogRef(lighthouseDir, catalogRef)
+	err = SaveKptfile(NewKptfile(filepath.Base(localKptDir), gitURL, fromDir, ref, sha), localKptFile)
+	if err != nil {
+		return err
+	}
 
-		fromDir := filepath.Join("/packs", packName, ".lighthouse", name) //nolint:gocritic
-		gitURL = strings.TrimSuffix(gitURL, ".git")
-		text := fmt.Sprintf(kptFile, name, sha, gitURL, fromDir)
-		err = os.WriteFile(localKptFile, []byte(text), files.DefaultFileWritePermissions)
-		if err != nil {
-			return errors.Wrapf(err, "failed to save file %s", localKptFile)
-		}
+	log.Logger().Infof("created %s", localKptFile)
+	return nil
+}
 
-		log.Logger().Infof("created %s", localKptFile)
+// isMissingKptfile returns true if the pipeline folder of the pack has triggers which are copied with kpt rather than
+// using uses: inheritance and the source has no local Kptfile for it
+func (o *ImportOptions) isMissingKptfile(lighthouseDir, name string) (bool, error) {
+	dir := filepath.Join(lighthouseDir, name)
+	triggerFile := filepath.Join(dir, "triggers.yaml")
+	exists, err := files.FileExists(triggerFile)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to check if file exists %s", triggerFile)
 	}
-	return nil
+	if !exists {
+		return false, nil
+	}
+
+	// let's check if we have a local Kptfile for this trigger folder
+	localKptFile := filepath.Join(o.Dir, ".lighthouse", name, KptfileName)
+	exists, err = files.FileExists(localKptFile)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to check if file exists %s", localKptFile)
+	}
+	if exists {
+		return false, nil
+	}
+
+	hasUses, err := CheckForUsesImage(dir, triggerFile)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to check for image: uses:sourceURI")
+	}
+	return !hasUses, nil
+}
+
+// pipelineCatalogRef returns the git ref of the pipeline catalog clone
+func (o *ImportOptions) pipelineCatalogRef(lighthouseDir, catalogRef string) string {
+	if catalogRef != "" {
+		return catalogRef
+	}
+	branch, err := gitclient.Branch(o.Git(), lighthouseDir)
+	if err == nil && branch != "" && branch != "HEAD" {
+		return branch
+	}
+	return defaultGitBranch
 }
 
 // CheckForUsesImage checks if the given dir and trigger file has a uses: image
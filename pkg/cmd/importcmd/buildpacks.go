@@ -2,6 +2,7 @@ package importcmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -15,7 +16,6 @@ import (
 	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/yamls"
 
-	jxdraft "github.com/jenkins-x-plugins/jx-project/pkg/draft"
 	"github.com/jenkins-x/jx-logging/v3/pkg/log"
 	"github.com/pkg/errors"
 )
@@ -28,6 +28,13 @@ type InvokeDraftPack struct {
 	Jenkinsfile     string
 	InitialisedGit  bool
 	DisableAddFiles bool
+	// Out where to write the pack detection output, defaults to os.Stdout
+	Out io.Writer
+
+	// PacksDir the packs directory of the pipeline catalog which is populated by InvokeDraftPack
+	PacksDir string
+	// Catalog the pipeline catalog which is populated by InvokeDraftPack
+	Catalog *v1alpha1.PipelineCatalogSource
 }
 
 // InitBuildPacks initialise the build packs
@@ -36,6 +43,7 @@ func (o *ImportOptions) InitBuildPacks(i *InvokeDraftPack) (string, *v1.TeamSett
 	if err != nil {
 		return "", settings, err
 	}
+	i.Catalog = bp
 
 	if o.PipelineCatalogDir != "" {
 		log.Logger().Infof("using the pipeline catalog dir %s", termcolor.ColorInfo(o.PipelineCatalogDir))
@@ -71,6 +79,14 @@ func (o *ImportOptions) PickPipelineCatalog(i *InvokeDraftPack) (*v1alpha1.Pipel
 		return nil, nil, errors.Errorf("no Dev Environment git clone dir")
 	}
 	settings := &o.DevEnv.Spec.TeamSettings
+	err := o.loadDevPlaceholders(devEnvCloneDir)
+	if err != nil {
+		return nil, settings, err
+	}
+	err = o.loadDevDockerfileSettings(devEnvCloneDir)
+	if err != nil {
+		return nil, settings, err
+	}
 	pipelineCatalogsFile := filepath.Join(devEnvCloneDir, "extensions", v1alpha1.PipelineCatalogFileName)
 	exists, err := files.FileExists(pipelineCatalogsFile)
 	if err != nil {
@@ -140,6 +156,7 @@ func (o *ImportOptions) InvokeDraftPack(i *InvokeDraftPack) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	i.PacksDir = packsDir
 
 	// let's assume Jenkins X import mode
 	//
@@ -150,11 +167,6 @@ func (o *ImportOptions) InvokeDraftPack(i *InvokeDraftPack) (string, error) {
 	dir := i.Dir
 	customDraftPack := i.CustomDraftPack
 
-	pomName := filepath.Join(dir, "pom.xml")
-	gradleName := filepath.Join(dir, "build.gradle")
-	jenkinsPluginsName := filepath.Join(dir, "plugins.txt")
-	packagerConfigName := filepath.Join(dir, "packager-config.yml")
-	envChart := filepath.Join(dir, "env", "Chart.yaml")
 	lpack := ""
 
 	if customDraftPack != "" {
@@ -172,84 +184,21 @@ func (o *ImportOptions) InvokeDraftPack(i *InvokeDraftPack) (string, error) {
 	}
 
 	if lpack == "" {
-		if exists, err := files.FileExists(pomName); err == nil && exists {
-			pack, err := PomFlavour(packsDir, pomName)
-			if err != nil {
-				return "", err
-			}
-			lpack = filepath.Join(packsDir, pack)
-
-			exists, _ = files.DirExists(lpack)
-			if !exists {
-				log.Logger().Warn("defaulting to maven pack")
-				lpack = filepath.Join(packsDir, "maven")
-			}
-		} else if exists, err := files.FileExists(gradleName); err == nil && exists {
-			lpack = filepath.Join(packsDir, "gradle")
-		} else if exists, err := files.FileExists(jenkinsPluginsName); err == nil && exists {
-			lpack = filepath.Join(packsDir, "jenkins")
-		} else if exists, err := files.FileExists(packagerConfigName); err == nil && exists {
-			lpack = filepath.Join(packsDir, "cwp")
-		} else if exists, err := files.FileExists(envChart); err == nil && exists {
-			lpack = filepath.Join(packsDir, "environment")
-		} else {
-			// pack detection time
-			lpack, err = jxdraft.DoPackDetectionForBuildPack(os.Stdout, dir, packsDir)
-
-			if err != nil {
-				if lpack == "" {
-					// let's detect docker and/or helm
-
-					// TODO one day when our pipelines can include steps conditional on the presence of a file glob
-					// we can just use a single docker/helm package that does docker and/or helm
-					// but for now we've 3 separate packs for docker, docker-helm and helm
-					hasDocker := false
-					hasHelm := false
-
-					if exists, err2 := files.FileExists(filepath.Join(dir, "Dockerfile")); err2 == nil && exists {
-						hasDocker = true
-					}
-
-					// let's check for a helm pack
-					glob, err2 := filepath.Glob(filepath.Join(dir, "charts", "*", "Chart.yaml"))
-					if err2 != nil {
-						return "", errors.Wrapf(err, "failed to detect if there was a chart file in dir %s", dir)
-					}
-					if len(glob) == 0 {
-						glob, err2 = filepath.Glob(filepath.Join(dir, "*", "Chart.yaml"))
-						if err2 != nil {
-							return "", errors.Wrapf(err, "failed to detect if there was a chart file in dir %s", dir)
-						}
-					}
-					if len(glob) > 0 {
-						hasHelm = true
-					}
-
-					if hasDocker {
-						if hasHelm {
-							lpack = filepath.Join(packsDir, "docker-helm")
-							err = nil
-						} else {
-							lpack = filepath.Join(packsDir, "docker")
-							err = nil
-						}
-					} else if hasHelm {
-						lpack = filepath.Join(packsDir, "helm")
-						err = nil
-					}
-				}
-				if lpack == "" {
-					exists, err2 := files.FileExists(filepath.Join(dir, JenkinsfileName))
-					if exists && err2 == nil {
-						lpack = filepath.Join(packsDir, "custom-jenkins")
-						err = nil
-					}
-				}
-				if err != nil {
-					return "", err
-				}
-			}
+		// pack detection time
+		log.Logger().Infof("performing pack detection in folder %s", dir)
+		selected, candidates, err := SelectPack(&DetectContext{
+			Dir:      dir,
+			PacksDir: packsDir,
+			Out:      i.Out,
+		})
+		if err != nil {
+			return "", err
 		}
+		for _, c := range candidates {
+			log.Logger().Debugf("candidate pack %s with score %d from %s: %s", c.Pack, c.Score, c.Detector, c.Reason)
+		}
+		log.Logger().Infof("detected pack %s: %s", termcolor.ColorInfo(selected.Pack), selected.Reason)
+		lpack = filepath.Join(packsDir, selected.Pack)
 	}
 
 	pack := filepath.Base(lpack)
@@ -259,6 +208,12 @@ func (o *ImportOptions) InvokeDraftPack(i *InvokeDraftPack) (string, error) {
 	}
 	lpack = filepath.Join(packsDir, pack)
 
+	pack, err = o.PickGoLibraryPack(dir, packsDir, pack, customDraftPack != "")
+	if err != nil {
+		return pack, err
+	}
+	lpack = filepath.Join(packsDir, pack)
+
 	log.Logger().Infof("selected catalog folder: %s", termcolor.ColorInfo(pack))
 	i.CustomDraftPack = pack
 
@@ -273,6 +228,11 @@ func (o *ImportOptions) InvokeDraftPack(i *InvokeDraftPack) (string, error) {
 		log.Logger().Warnf("Failed to apply the build pack in %s due to %s", dir, err)
 	}
 
+	err = o.generateMissingDockerfile(dir, pack)
+	if err != nil {
+		return pack, errors.Wrapf(err, "failed to generate a Dockerfile")
+	}
+
 	// lets delete empty charts dir if a draft pack created one
 	exists, err := files.DirExists(chartsDir)
 	if err == nil && exists {
@@ -288,13 +248,25 @@ func (o *ImportOptions) InvokeDraftPack(i *InvokeDraftPack) (string, error) {
 		}
 	}
 
+	migrated, err := MigrateLighthouseKptfiles(dir)
+	if err != nil {
+		return pack, errors.Wrapf(err, "failed to migrate Kptfiles")
+	}
+	for _, path := range migrated {
+		log.Logger().Infof("migrated %s to %s", path, KptAPIVersion)
+	}
+
 	lighthouseDir := filepath.Join(packsDir, pack, ".lighthouse")
 	exists, err = files.DirExists(lighthouseDir)
 	if err != nil {
 		return pack, errors.Wrapf(err, "failed to detect lighthouse dir %s", lighthouseDir)
 	}
 	if exists {
-		err = o.createMissingLighthouseKptFiles(lighthouseDir, pack)
+		catalogRef := ""
+		if i.Catalog != nil {
+			catalogRef = i.Catalog.GitRef
+		}
+		err = o.createMissingLighthouseKptFiles(lighthouseDir, pack, catalogRef)
 		if err != nil {
 			return pack, errors.Wrapf(err, "failed to add missing Kptfiles for pipeline catalog")
 		}
@@ -360,6 +332,16 @@ func (o *ImportOptions) copyBuildPack(dest, src string) error {
 		o.PackFilter(p)
 	}
 
+	goTemplateFile := filepath.Join(".jx", GoTemplateFileName)
+	if p.Files[goTemplateFile] != nil {
+		exists, err := files.FileExists(filepath.Join(dest, goTemplateFile))
+		if err != nil {
+			return errors.Wrapf(err, "failed to check if file exists %s", filepath.Join(dest, goTemplateFile))
+		}
+		// the pack only renders all of the go templates if its configuration is used
+		o.AddedGoTemplateConfig = o.AddedGoTemplateConfig || !exists
+	}
+
 	_, packName := filepath.Split(src)
 	return p.SaveDir(dest, packName)
 }
@@ -0,0 +1,59 @@
+package importcmd
+
+import (
+	"path/filepath"
+	"strconv"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/constants"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// ApplyDeployOptions sets the knativeDeploy, canary.enabled and hpa.enabled values in the values.yaml of the
+// application chart from the deploy kind and deploy options
+func (o *ImportOptions) ApplyDeployOptions() error {
+	path := filepath.Join(o.Dir, ChartsDir, o.AppName, "values.yaml")
+	return modifyYAMLFile(path, o.applyDeployOptions)
+}
+
+// applyDeployOptions sets the deploy kind, canary and hpa values of the chart returning true if any were modified
+func (o *ImportOptions) applyDeployOptions(node *yaml.Node) bool {
+	modified := false
+	if o.DeployKind != "" {
+		modified = setMapBool(node, o.DeployKind == constants.DeployKindKnative, "knativeDeploy") || modified
+	}
+	modified = setMapBool(node, o.DeployOptions.Canary, "canary", "enabled") || modified
+	modified = setMapBool(node, o.DeployOptions.HPA, "hpa", "enabled") || modified
+	return modified
+}
+
+// setMapBool sets the boolean value at the path creating any missing maps if the value is true
+func setMapBool(node *yaml.Node, value bool, path ...string) bool {
+	text := strconv.FormatBool(value)
+	for i, key := range path {
+		if node.Kind != yaml.MappingNode {
+			return false
+		}
+		child := mapValue(node, key)
+		if child == nil {
+			if !value {
+				return false
+			}
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: yaml.NodeTagMap}
+			if i == len(path)-1 {
+				child = &yaml.Node{Kind: yaml.ScalarNode, Tag: yaml.NodeTagBool, Value: text}
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: yaml.NodeTagString, Value: key}, child)
+			if i == len(path)-1 {
+				return true
+			}
+		}
+		node = child
+	}
+	if node.Kind != yaml.ScalarNode || (node.Value == text && node.Tag == yaml.NodeTagBool) {
+		return false
+	}
+	node.Value = text
+	node.Tag = yaml.NodeTagBool
+	node.Style = 0
+	return true
+}
@@ -0,0 +1,88 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyDeployOptions(t *testing.T) {
+	values := `# should we use knative serve
+knativeDeploy: false
+canary:
+  enabled: false
+  host: myapp.example.com
+image:
+  repository: myapp
+`
+	testCases := []struct {
+		name          string
+		deployKind    string
+		deployOptions v1.DeployOptions
+		expected      string
+	}{
+		{
+			name:     "unset",
+			expected: values,
+		},
+		{
+			name:       "default",
+			deployKind: "default",
+			expected:   values,
+		},
+		{
+			name:          "knative",
+			deployKind:    "knative",
+			deployOptions: v1.DeployOptions{Canary: true, HPA: true},
+			expected: `# should we use knative serve
+knativeDeploy: true
+canary:
+  enabled: true
+  host: myapp.example.com
+image:
+  repository: myapp
+hpa:
+  enabled: true
+`,
+		},
+		{
+			name:          "default-hpa",
+			deployKind:    "default",
+			deployOptions: v1.DeployOptions{HPA: true},
+			expected: `# should we use knative serve
+knativeDeploy: false
+canary:
+  enabled: false
+  host: myapp.example.com
+image:
+  repository: myapp
+hpa:
+  enabled: true
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "charts", "myapp", "values.yaml")
+			writeTestFiles(t, dir, map[string]string{"charts/myapp/values.yaml": values})
+
+			o := &importcmd.ImportOptions{}
+			o.Dir = dir
+			o.AppName = "myapp"
+			o.DeployKind = tc.deployKind
+			o.DeployOptions = tc.deployOptions
+			err := o.ApplyDeployOptions()
+			require.NoError(t, err, "failed to apply the deploy options")
+
+			assertFileEquals(t, path, tc.expected)
+		})
+	}
+}
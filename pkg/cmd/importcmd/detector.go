@@ -0,0 +1,680 @@
+package importcmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Azure/draft/pkg/linguist"
+	"github.com/jenkins-x-plugins/jx-project/pkg/draft"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/yamls"
+	"github.com/pkg/errors"
+)
+
+// DetectFileName the name of the file in a pipeline catalog pack folder which contains the rules to detect the pack
+const DetectFileName = "detect.yaml"
+
+// the scores of the built in detectors which preserve the order in which packs were historically chosen
+const (
+	ScorePomFlavour      = 100
+	ScoreGradle          = 90
+	ScoreJenkinsPlugins  = 80
+	ScorePackagerConfig  = 70
+	ScoreEnvironment     = 60
+	ScoreNodeFlavour     = 55
+	ScorePythonFlavour   = 55
+	ScoreGoFlavour       = 55
+	ScoreLinguistMaximum = 50
+	ScoreLinguistMinimum = 10
+	ScoreDockerHelm      = 8
+	ScoreJenkinsfile     = 5
+)
+
+// PackCandidate a pipeline catalog pack which could be used for the source code
+type PackCandidate struct {
+	// Pack the name of the pack folder in the pipeline catalog
+	Pack string `json:"pack"`
+	// Score the confidence of the detector that the pack should be used. Higher scores win
+	Score int `json:"score"`
+	// Detector the name of the detector which found the candidate
+	Detector string `json:"detector"`
+	// Reason describes the rule which matched
+	Reason string `json:"reason,omitempty"`
+	// Share the percentage of the source code in the language of the pack detected by linguist
+	Share float64 `json:"share,omitempty"`
+}
+
+// DetectContext the source code and pipeline catalog used to detect the pack
+type DetectContext struct {
+	// Dir the directory of the source code
+	Dir string
+	// PacksDir the packs directory of the pipeline catalog
+	PacksDir string
+	// Out where to write detection output, defaults to os.Stdout
+	Out io.Writer
+
+	languages    []*linguist.Language
+	languagesErr error
+	languagesSet bool
+}
+
+// Languages returns the languages detected by linguist ordered by likelihood. The result is cached
+func (c *DetectContext) Languages() ([]*linguist.Language, error) {
+	if !c.languagesSet {
+		c.languages, c.languagesErr = draft.DetectLanguages(c.Dir)
+		c.languagesSet = true
+	}
+	return c.languages, c.languagesErr
+}
+
+// LanguageShare returns the percentage of the source code in any of the languages detected by linguist
+func (c *DetectContext) LanguageShare(languages ...string) float64 {
+	langs, err := c.Languages()
+	if err != nil {
+		return 0
+	}
+	share := 0.0
+	for _, lang := range langs {
+		if matchesLanguage(lang.Language, languages) {
+			share += lang.Percent
+		}
+	}
+	return share
+}
+
+// IsDominantLanguage returns true if the languages have a larger share of the source code than any other language
+// detected by linguist which has a flavour detector or a pack. Languages without either such as JSON, YAML and
+// Markdown are ignored. If linguist cannot detect any languages the result is true
+func (c *DetectContext) IsDominantLanguage(languages ...string) bool {
+	langs, err := c.Languages()
+	if err != nil || len(langs) == 0 {
+		return true
+	}
+	share := c.LanguageShare(languages...)
+	others := map[string]float64{}
+	for _, lang := range langs {
+		if matchesLanguage(lang.Language, languages) {
+			continue
+		}
+		key := ""
+		for _, group := range flavourLanguages {
+			if matchesLanguage(lang.Language, group) {
+				key = group[0]
+			}
+		}
+		if key == "" && c.PackExists(strings.ToLower(lang.Language)) {
+			key = lang.Language
+		}
+		if key != "" {
+			others[key] += lang.Percent
+		}
+	}
+	for _, otherShare := range others {
+		if otherShare >= share {
+			return false
+		}
+	}
+	return true
+}
+
+// flavourScore returns the score and share of a flavour detector for source code in the languages. The flavour
+// detectors only score above linguist when their languages are dominant so that tooling files such as a
+// package.json or requirements.txt in a go repository do not override the language of the source code. Otherwise
+// the score is derived from the share of the languages in the same way as the linguist detector
+func flavourScore(c *DetectContext, score int, languages []string) (int, float64) {
+	share := c.LanguageShare(languages...)
+	if c.IsDominantLanguage(languages...) {
+		return score, share
+	}
+	return linguistScore(share), share
+}
+
+// linguistScore returns the score of a language with the given percentage of the source code
+func linguistScore(percent float64) int {
+	return ScoreLinguistMinimum + int(percent*float64(ScoreLinguistMaximum-ScoreLinguistMinimum)/100)
+}
+
+func matchesLanguage(language string, languages []string) bool {
+	for _, l := range languages {
+		if strings.EqualFold(language, l) {
+			return true
+		}
+	}
+	return false
+}
+
+// FileExists returns true if the given path relative to the source directory is a file
+func (c *DetectContext) FileExists(path string) bool {
+	exists, err := files.FileExists(filepath.Join(c.Dir, path))
+	return err == nil && exists
+}
+
+// PackExists returns true if the pipeline catalog contains the given pack
+func (c *DetectContext) PackExists(pack string) bool {
+	exists, err := files.DirExists(filepath.Join(c.PacksDir, pack))
+	return err == nil && exists
+}
+
+func (c *DetectContext) out() io.Writer {
+	if c.Out == nil {
+		return os.Stdout
+	}
+	return c.Out
+}
+
+// Detector detects which pipeline catalog packs could be used for some source code
+type Detector interface {
+	// Name the name of the detector
+	Name() string
+	// Detect returns the candidate packs for the source code
+	Detect(c *DetectContext) ([]*PackCandidate, error)
+}
+
+var (
+	// nodeLanguages the linguist languages of node source code. linguist reports TypeScript as javascript
+	nodeLanguages   = []string{"JavaScript", "TypeScript"}
+	pythonLanguages = []string{"Python"}
+	goLanguages     = []string{"Go"}
+
+	// flavourLanguages the languages of the flavour detectors which only score above linguist when dominant
+	flavourLanguages = [][]string{nodeLanguages, pythonLanguages, goLanguages}
+
+	detectorsLock sync.Mutex
+	detectors     = []Detector{
+		&PomFlavourDetector{},
+		&GradleFlavourDetector{},
+		&FileDetector{File: "plugins.txt", Pack: "jenkins", Score: ScoreJenkinsPlugins},
+		&FileDetector{File: "packager-config.yml", Pack: "cwp", Score: ScorePackagerConfig},
+		&FileDetector{File: filepath.Join("env", "Chart.yaml"), Pack: "environment", Score: ScoreEnvironment},
+		&NodeFlavourDetector{},
+		&PythonFlavourDetector{},
+		&GoFlavourDetector{},
+		&LinguistDetector{},
+		&DockerHelmDetector{},
+		&FileDetector{File: JenkinsfileName, Pack: "custom-jenkins", Score: ScoreJenkinsfile},
+		&CatalogDetector{},
+	}
+)
+
+// RegisterDetector adds a detector to the registry used to detect packs
+func RegisterDetector(d Detector) {
+	detectorsLock.Lock()
+	defer detectorsLock.Unlock()
+	detectors = append(detectors, d)
+}
+
+// Detectors returns the registered detectors
+func Detectors() []Detector {
+	detectorsLock.Lock()
+	defer detectorsLock.Unlock()
+	return append([]Detector{}, detectors...)
+}
+
+// DetectPacks runs all of the registered detectors returning the candidate packs ordered by descending score.
+// Candidates with the same score are ordered by the linguist share of their language and then keep the order of
+// the detectors
+func DetectPacks(c *DetectContext) ([]*PackCandidate, error) {
+	var answer []*PackCandidate
+	for _, d := range Detectors() {
+		candidates, err := d.Detect(c)
+		if err != nil {
+			return answer, errors.Wrapf(err, "failed to detect packs with detector %s", d.Name())
+		}
+		for _, candidate := range candidates {
+			if candidate.Detector == "" {
+				candidate.Detector = d.Name()
+			}
+			answer = append(answer, candidate)
+		}
+	}
+	sort.SliceStable(answer, func(i, j int) bool {
+		if answer[i].Score != answer[j].Score {
+			return answer[i].Score > answer[j].Score
+		}
+		return answer[i].Share > answer[j].Share
+	})
+	return answer, nil
+}
+
+// SelectPack returns the candidate pack with the highest score
+func SelectPack(c *DetectContext) (*PackCandidate, []*PackCandidate, error) {
+	candidates, err := DetectPacks(c)
+	if err != nil {
+		return nil, candidates, err
+	}
+	if len(candidates) == 0 {
+		return nil, candidates, errors.Errorf("could not detect a pack for %s using the packs from %s", c.Dir, c.PacksDir)
+	}
+	return candidates[0], candidates, nil
+}
+
+// FileDetector detects a pack if a file exists
+type FileDetector struct {
+	// File the path of the file relative to the source directory
+	File string
+	// Pack the pack to use if the file exists
+	Pack string
+	// Score the score of the candidate
+	Score int
+}
+
+// Name the name of the detector
+func (d *FileDetector) Name() string {
+	return "File"
+}
+
+// Detect returns the pack if the file exists
+func (d *FileDetector) Detect(c *DetectContext) ([]*PackCandidate, error) {
+	if !c.FileExists(d.File) {
+		return nil, nil
+	}
+	return []*PackCandidate{
+		{
+			Pack:   d.Pack,
+			Score:  d.Score,
+			Reason: fmt.Sprintf("found %s", filepath.ToSlash(d.File)),
+		},
+	}, nil
+}
+
+// PomFlavourDetector detects the maven pack from the contents of the pom.xml
+type PomFlavourDetector struct{}
+
+// Name the name of the detector
+func (d *PomFlavourDetector) Name() string {
+	return "PomFlavour"
+}
+
+// Detect returns the maven pack for the pom.xml
+func (d *PomFlavourDetector) Detect(c *DetectContext) ([]*PackCandidate, error) {
+	if !c.FileExists("pom.xml") {
+		return nil, nil
+	}
+	pack, err := PomFlavour(c.PacksDir, filepath.Join(c.Dir, "pom.xml"))
+	if err != nil {
+		return nil, err
+	}
+	reason := fmt.Sprintf("found pom.xml using the %s flavour", pack)
+	if !c.PackExists(pack) {
+		reason = fmt.Sprintf("found pom.xml but there is no %s pack so defaulting to maven", pack)
+		pack = MAVEN
+	}
+	return []*PackCandidate{
+		{
+			Pack:   pack,
+			Score:  ScorePomFlavour,
+			Reason: reason,
+		},
+	}, nil
+}
+
+// GradleFlavourDetector detects the gradle pack from the gradle build files
+type GradleFlavourDetector struct{}
+
+// Name the name of the detector
+func (d *GradleFlavourDetector) Name() string {
+	return "GradleFlavour"
+}
+
+// Detect returns the gradle pack for the build files
+func (d *GradleFlavourDetector) Detect(c *DetectContext) ([]*PackCandidate, error) {
+	exists, err := HasGradleBuildFile(c.Dir)
+	if err != nil || !exists {
+		return nil, err
+	}
+	pack, err := GradleFlavour(c.PacksDir, c.Dir)
+	if err != nil {
+		return nil, err
+	}
+	return []*PackCandidate{
+		{
+			Pack:   pack,
+			Score:  ScoreGradle,
+			Reason: fmt.Sprintf("found a gradle build file using the %s flavour", pack),
+		},
+	}, nil
+}
+
+// NodeFlavourDetector detects the node pack from the package.json
+type NodeFlavourDetector struct{}
+
+// Name the name of the detector
+func (d *NodeFlavourDetector) Name() string {
+	return "NodeFlavour"
+}
+
+// Detect returns the node pack for the package.json
+func (d *NodeFlavourDetector) Detect(c *DetectContext) ([]*PackCandidate, error) {
+	if !c.FileExists("package.json") {
+		return nil, nil
+	}
+	pack, err := NodeFlavour(c.PacksDir, c.Dir)
+	if err != nil {
+		return nil, err
+	}
+	score, share := flavourScore(c, ScoreNodeFlavour, nodeLanguages)
+	return []*PackCandidate{
+		{
+			Pack:   pack,
+			Score:  score,
+			Reason: fmt.Sprintf("found package.json using the %s flavour", pack),
+			Share:  share,
+		},
+	}, nil
+}
+
+// PythonFlavourDetector detects the python pack from the python project files
+type PythonFlavourDetector struct{}
+
+// Name the name of the detector
+func (d *PythonFlavourDetector) Name() string {
+	return "PythonFlavour"
+}
+
+// Detect returns the python pack for the project files
+func (d *PythonFlavourDetector) Detect(c *DetectContext) ([]*PackCandidate, error) {
+	exists, err := HasPythonProjectFile(c.Dir)
+	if err != nil || !exists {
+		return nil, err
+	}
+	pack, err := PythonFlavour(c.PacksDir, c.Dir)
+	if err != nil {
+		return nil, err
+	}
+	score, share := flavourScore(c, ScorePythonFlavour, pythonLanguages)
+	return []*PackCandidate{
+		{
+			Pack:   pack,
+			Score:  score,
+			Reason: fmt.Sprintf("found a python project file using the %s flavour", pack),
+			Share:  share,
+		},
+	}, nil
+}
+
+// GoFlavourDetector detects the go pack from the go.mod or go.work files
+type GoFlavourDetector struct{}
+
+// Name the name of the detector
+func (d *GoFlavourDetector) Name() string {
+	return "GoFlavour"
+}
+
+// Detect returns the go pack for the go.mod or go.work files
+func (d *GoFlavourDetector) Detect(c *DetectContext) ([]*PackCandidate, error) {
+	if !c.FileExists("go.mod") && !c.FileExists("go.work") {
+		return nil, nil
+	}
+	pack, err := GoFlavour(c.PacksDir, c.Dir)
+	if err != nil {
+		return nil, err
+	}
+	score, share := flavourScore(c, ScoreGoFlavour, goLanguages)
+	return []*PackCandidate{
+		{
+			Pack:   pack,
+			Score:  score,
+			Reason: fmt.Sprintf("found a go module using the %s flavour", pack),
+			Share:  share,
+		},
+	}, nil
+}
+
+// LinguistDetector detects packs whose name matches a language detected by linguist
+type LinguistDetector struct{}
+
+// Name the name of the detector
+func (d *LinguistDetector) Name() string {
+	return "Linguist"
+}
+
+// Detect returns a candidate for each detected language which has a pack with the same name
+func (d *LinguistDetector) Detect(c *DetectContext) ([]*PackCandidate, error) {
+	langs, err := c.Languages()
+	if err != nil || len(langs) == 0 {
+		// lets allow the other detectors to find a pack
+		return nil, nil
+	}
+	packs, err := os.ReadDir(c.PacksDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read dir %s", c.PacksDir)
+	}
+	var answer []*PackCandidate
+	for _, lang := range langs {
+		fmt.Fprintf(c.out(), "--> Draft detected %s (%f%%)\n", lang.Language, lang.Percent)
+		for _, f := range packs {
+			if f.IsDir() && strings.EqualFold(lang.Language, f.Name()) {
+				answer = append(answer, &PackCandidate{
+					Pack:   f.Name(),
+					Score:  linguistScore(lang.Percent),
+					Reason: fmt.Sprintf("linguist detected %s (%.1f%%)", lang.Language, lang.Percent),
+					Share:  lang.Percent,
+				})
+				break
+			}
+		}
+	}
+	return answer, nil
+}
+
+// DockerHelmDetector detects the docker, docker-helm or helm packs from a Dockerfile and helm charts
+type DockerHelmDetector struct{}
+
+// Name the name of the detector
+func (d *DockerHelmDetector) Name() string {
+	return "DockerHelm"
+}
+
+// Detect returns the docker and/or helm pack
+func (d *DockerHelmDetector) Detect(c *DetectContext) ([]*PackCandidate, error) {
+	// TODO one day when our pipelines can include steps conditional on the presence of a file glob
+	// we can just use a single docker/helm package that does docker and/or helm
+	// but for now we've 3 separate packs for docker, docker-helm and helm
+	hasDocker := c.FileExists("Dockerfile")
+
+	// let's check for a helm pack
+	glob, err := filepath.Glob(filepath.Join(c.Dir, "charts", "*", "Chart.yaml"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to detect if there was a chart file in dir %s", c.Dir)
+	}
+	if len(glob) == 0 {
+		glob, err = filepath.Glob(filepath.Join(c.Dir, "*", "Chart.yaml"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to detect if there was a chart file in dir %s", c.Dir)
+		}
+	}
+	hasHelm := len(glob) > 0
+
+	pack := ""
+	reason := ""
+	switch {
+	case hasDocker && hasHelm:
+		pack = "docker-helm"
+		reason = "found a Dockerfile and a helm chart"
+	case hasDocker:
+		pack = "docker"
+		reason = "found a Dockerfile"
+	case hasHelm:
+		pack = "helm"
+		reason = "found a helm chart"
+	default:
+		return nil, nil
+	}
+	return []*PackCandidate{
+		{
+			Pack:   pack,
+			Score:  ScoreDockerHelm,
+			Reason: reason,
+		},
+	}, nil
+}
+
+// DetectRules the rules in the detect.yaml file of a pipeline catalog pack folder
+type DetectRules struct {
+	// Rules the rules which are evaluated in order. The highest scoring matching rule is used
+	Rules []DetectRule `json:"rules,omitempty"`
+}
+
+// DetectRule a rule to detect if a pack should be used. All of the conditions which are specified must match
+type DetectRule struct {
+	// Files globs relative to the source directory which must each match at least one file
+	Files []string `json:"files,omitempty"`
+	// Contains maps a glob relative to the source directory to a regular expression which must match the
+	// contents of at least one of the matching files
+	Contains map[string]string `json:"contains,omitempty"`
+	// Languages one of which must have been detected by linguist
+	Languages []string `json:"languages,omitempty"`
+	// Score the score of the candidate if the rule matches
+	Score int `json:"score,omitempty"`
+	// Reason describes the rule
+	Reason string `json:"reason,omitempty"`
+}
+
+// CatalogDetector detects packs using the detect.yaml files in the pack folders of the pipeline catalog so that
+// catalogs can support new kinds of source code without changing jx-project
+type CatalogDetector struct{}
+
+// Name the name of the detector
+func (d *CatalogDetector) Name() string {
+	return "Catalog"
+}
+
+// Detect returns a candidate for each pack with a matching detect.yaml rule
+func (d *CatalogDetector) Detect(c *DetectContext) ([]*PackCandidate, error) {
+	packs, err := os.ReadDir(c.PacksDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read dir %s", c.PacksDir)
+	}
+	var answer []*PackCandidate
+	for _, f := range packs {
+		if !f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(c.PacksDir, f.Name(), DetectFileName)
+		exists, err := files.FileExists(path)
+		if err != nil {
+			return answer, errors.Wrapf(err, "failed to check if file exists %s", path)
+		}
+		if !exists {
+			continue
+		}
+		rules := &DetectRules{}
+		err = yamls.LoadFile(path, rules)
+		if err != nil {
+			return answer, errors.Wrapf(err, "failed to load %s", path)
+		}
+
+		var best *PackCandidate
+		for i := range rules.Rules {
+			rule := &rules.Rules[i]
+			matched, err := rule.Matches(c)
+			if err != nil {
+				return answer, errors.Wrapf(err, "failed to evaluate rule %d in %s", i+1, path)
+			}
+			if matched && (best == nil || rule.Score > best.Score) {
+				reason := rule.Reason
+				if reason == "" {
+					reason = rule.String()
+				}
+				best = &PackCandidate{
+					Pack:   f.Name(),
+					Score:  rule.Score,
+					Reason: fmt.Sprintf("%s rule: %s", filepath.ToSlash(filepath.Join(f.Name(), DetectFileName)), reason),
+				}
+			}
+		}
+		if best != nil {
+			answer = append(answer, best)
+		}
+	}
+	return answer, nil
+}
+
+// Matches returns true if the rule has at least one condition and all of its conditions match the source code
+func (r *DetectRule) Matches(c *DetectContext) (bool, error) {
+	if len(r.Files) == 0 && len(r.Contains) == 0 && len(r.Languages) == 0 {
+		return false, nil
+	}
+	for _, pattern := range r.Files {
+		matches, err := filepath.Glob(filepath.Join(c.Dir, pattern))
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid glob %s", pattern)
+		}
+		if len(matches) == 0 {
+			return false, nil
+		}
+	}
+	for pattern, expression := range r.Contains {
+		matched, err := globContains(c.Dir, pattern, expression)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	if len(r.Languages) > 0 {
+		langs, err := c.Languages()
+		if err != nil {
+			return false, nil
+		}
+		found := false
+		for _, lang := range langs {
+			for _, name := range r.Languages {
+				if strings.EqualFold(lang.Language, name) {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// String returns a description of the conditions of the rule
+func (r *DetectRule) String() string {
+	var conditions []string
+	if len(r.Files) > 0 {
+		conditions = append(conditions, "files "+strings.Join(r.Files, ", "))
+	}
+	var keys []string
+	for k := range r.Contains {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		conditions = append(conditions, fmt.Sprintf("%s contains %s", k, r.Contains[k]))
+	}
+	if len(r.Languages) > 0 {
+		conditions = append(conditions, "languages "+strings.Join(r.Languages, ", "))
+	}
+	return strings.Join(conditions, " and ")
+}
+
+// globContains returns true if any file matching the glob in the directory matches the regular expression
+func globContains(dir, pattern, expression string) (bool, error) {
+	re, err := regexp.Compile(expression)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid regular expression %s", expression)
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid glob %s", pattern)
+	}
+	for _, path := range matches {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			// lets ignore directories and unreadable files
+			continue
+		}
+		if re.Match(data) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
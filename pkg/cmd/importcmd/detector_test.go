@@ -0,0 +1,244 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type customDetector struct{}
+
+func (d *customDetector) Name() string {
+	return "Custom"
+}
+
+func (d *customDetector) Detect(c *importcmd.DetectContext) ([]*importcmd.PackCandidate, error) {
+	if !c.FileExists("my.build") {
+		return nil, nil
+	}
+	return []*importcmd.PackCandidate{
+		{
+			Pack:   "go",
+			Score:  200,
+			Reason: "found my.build",
+		},
+	}, nil
+}
+
+// tieDetector returns candidates for different languages with the same score
+type tieDetector struct{}
+
+func (d *tieDetector) Name() string {
+	return "Tie"
+}
+
+func (d *tieDetector) Detect(c *importcmd.DetectContext) ([]*importcmd.PackCandidate, error) {
+	if !c.FileExists("tie.build") {
+		return nil, nil
+	}
+	return []*importcmd.PackCandidate{
+		{
+			Pack:   "maven",
+			Score:  200,
+			Reason: "found tie.build with less java",
+			Share:  10,
+		},
+		{
+			Pack:   "go",
+			Score:  200,
+			Reason: "found tie.build with more go",
+			Share:  20,
+		},
+	}, nil
+}
+
+func TestSelectPack(t *testing.T) {
+	importcmd.RegisterDetector(&customDetector{})
+	importcmd.RegisterDetector(&tieDetector{})
+
+	packsDir := filepath.Join("test_data", "detect", "packs")
+	testCases := []struct {
+		source   string
+		pack     string
+		detector string
+	}{
+		{
+			source:   "maven",
+			pack:     "maven",
+			detector: "PomFlavour",
+		},
+		{
+			source:   "quarkus",
+			pack:     "quarkus",
+			detector: "Catalog",
+		},
+		{
+			source:   "custom",
+			pack:     "go",
+			detector: "Custom",
+		},
+		{
+			source:   "tie",
+			pack:     "go",
+			detector: "Tie",
+		},
+	}
+	for _, tc := range testCases {
+		c := &importcmd.DetectContext{
+			Dir:      filepath.Join("test_data", "detect", "source", tc.source),
+			PacksDir: packsDir,
+			Out:      io.Discard,
+		}
+		selected, candidates, err := importcmd.SelectPack(c)
+		require.NoError(t, err, "failed to select pack for %s", tc.source)
+		require.NotNil(t, selected, "no pack selected for %s", tc.source)
+		assert.Equal(t, tc.pack, selected.Pack, "pack for %s", tc.source)
+		assert.Equal(t, tc.detector, selected.Detector, "detector for %s", tc.source)
+		assert.NotEmpty(t, selected.Reason, "reason for %s", tc.source)
+
+		for i := 1; i < len(candidates); i++ {
+			assert.GreaterOrEqual(t, candidates[i-1].Score, candidates[i].Score, "candidates for %s should be ordered by score", tc.source)
+		}
+	}
+}
+
+func TestDetectFileNotCopiedFromPack(t *testing.T) {
+	p, err := importcmd.FromDir(filepath.Join("test_data", "detect", "packs", "quarkus"))
+	require.NoError(t, err, "failed to load pack")
+	defer p.Close()
+
+	assert.Contains(t, p.Files, "Dockerfile")
+	assert.NotContains(t, p.Files, importcmd.DetectFileName)
+}
+
+const (
+	goMainFile = `package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+)
+
+func main() {
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "Hello from %s\n", r.URL.Path)
+	})
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+	err := http.ListenAndServe(":"+port, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to serve: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+`
+
+	huskyPackageJSON = `{
+  "name": "myapp",
+  "private": true,
+  "scripts": {
+    "prepare": "husky install"
+  },
+  "devDependencies": {
+    "@commitlint/cli": "^17.0.0",
+    "@commitlint/config-conventional": "^17.0.0",
+    "husky": "^8.0.0"
+  }
+}
+`
+)
+
+func TestSelectPackUsesDominantLanguage(t *testing.T) {
+	packsDir := t.TempDir()
+	writeTestFiles(t, packsDir, map[string]string{
+		"go/Dockerfile":         "FROM scratch\n",
+		"javascript/Dockerfile": "FROM scratch\n",
+		"python/Dockerfile":     "FROM scratch\n",
+	})
+
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		pack     string
+		detector string
+	}{
+		{
+			name: "go with husky",
+			files: map[string]string{
+				"go.mod":               "module github.com/myorg/myapp\n\ngo 1.22\n",
+				"main.go":              goMainFile,
+				"package.json":         huskyPackageJSON,
+				"commitlint.config.js": "module.exports = {extends: ['@commitlint/config-conventional']};\n",
+				".husky/commit-msg":    "#!/bin/sh\nnpx --no -- commitlint --edit \"$1\"\n",
+			},
+			pack:     "go",
+			detector: "GoFlavour",
+		},
+		{
+			name: "go with mkdocs",
+			files: map[string]string{
+				"go.mod":           "module github.com/myorg/myapp\n\ngo 1.22\n",
+				"main.go":          goMainFile,
+				"requirements.txt": "mkdocs==1.5.3\nmkdocs-material==9.5.0\n",
+				"mkdocs.yml":       "site_name: myapp\nnav:\n- Home: index.md\n",
+				"docs/index.md":    "# myapp\n\nThe documentation of myapp.\n",
+			},
+			pack:     "go",
+			detector: "GoFlavour",
+		},
+		{
+			name: "python",
+			files: map[string]string{
+				"requirements.txt": "flask==3.0.0\n",
+				"app.py":           "from flask import Flask\n\napp = Flask(__name__)\n\n\n@app.route('/')\ndef hello():\n    return 'hello'\n",
+			},
+			pack:     "python",
+			detector: "PythonFlavour",
+		},
+		{
+			name: "node",
+			files: map[string]string{
+				"package.json": `{"name": "myapp", "main": "index.js"}`,
+				"index.js":     "const http = require('http');\n\nhttp.createServer((req, res) => res.end('hello')).listen(8080);\n",
+			},
+			pack:     "javascript",
+			detector: "NodeFlavour",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestFiles(t, dir, tc.files)
+
+			c := &importcmd.DetectContext{
+				Dir:      dir,
+				PacksDir: packsDir,
+				Out:      io.Discard,
+			}
+			selected, candidates, err := importcmd.SelectPack(c)
+			require.NoError(t, err, "failed to select pack")
+			require.NotNil(t, selected, "no pack selected")
+			assert.Equal(t, tc.pack, selected.Pack, "pack")
+			assert.Equal(t, tc.detector, selected.Detector, "detector")
+
+			for _, candidate := range candidates {
+				switch candidate.Detector {
+				case "NodeFlavour", "PythonFlavour", "GoFlavour":
+					if candidate.Detector != tc.detector {
+						assert.LessOrEqual(t, candidate.Score, importcmd.ScoreLinguistMaximum, "the %s candidate should not score above linguist", candidate.Detector)
+					}
+				}
+			}
+		})
+	}
+}
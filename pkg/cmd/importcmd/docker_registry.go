@@ -0,0 +1,35 @@
+package importcmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DockerRegistryConfigMapName the name of the ConfigMap in the dev namespace with the docker registry of the cluster
+	DockerRegistryConfigMapName = "jenkins-x-docker-registry"
+
+	// dockerRegistryConfigMapKey the key of the docker registry host in the ConfigMap
+	dockerRegistryConfigMapKey = "docker.registry"
+)
+
+// ClusterDockerRegistry returns the docker registry host of the cluster from the jenkins-x-docker-registry ConfigMap
+// in the dev namespace or an empty string if there is none
+func (o *ImportOptions) ClusterDockerRegistry() (string, error) {
+	if o.KubeClient == nil {
+		return "", nil
+	}
+	ctx := context.Background()
+	cm, err := o.KubeClient.CoreV1().ConfigMaps(o.Namespace).Get(ctx, DockerRegistryConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to get ConfigMap %s in namespace %s", DockerRegistryConfigMapName, o.Namespace)
+	}
+	return strings.TrimSpace(cm.Data[dockerRegistryConfigMapKey]), nil
+}
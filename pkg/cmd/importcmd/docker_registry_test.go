@@ -0,0 +1,81 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/testimports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDockerRegistryFromCluster(t *testing.T) {
+	testCases := []struct {
+		name     string
+		flag     string
+		cluster  string
+		expected string
+	}{
+		{
+			name:     "cluster",
+			cluster:  "ghcr.io",
+			expected: "ghcr.io",
+		},
+		{
+			name:     "flag",
+			flag:     "quay.io",
+			cluster:  "ghcr.io",
+			expected: "quay.io",
+		},
+		{
+			name: "none",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &importcmd.ImportOptions{}
+			testimports.SetFakeClients(t, o, false)
+			o.DockerRegistry = tc.flag
+			if tc.cluster != "" {
+				_, err := o.KubeClient.CoreV1().ConfigMaps(o.Namespace).Create(context.Background(), &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      importcmd.DockerRegistryConfigMapName,
+						Namespace: o.Namespace,
+					},
+					Data: map[string]string{"docker.registry": tc.cluster},
+				}, metav1.CreateOptions{})
+				require.NoError(t, err, "failed to create ConfigMap")
+			}
+
+			err := o.DefaultsFromTeamSettings()
+			require.NoError(t, err, "failed to default from team settings")
+			assert.Equal(t, tc.expected, o.DockerRegistry, "o.DockerRegistry")
+		})
+	}
+}
+
+func TestUnknownDockerRegistryPlaceholder(t *testing.T) {
+	o := importcmd.ImportOptions{}
+	o.Dir = t.TempDir()
+	o.AppName = "myapp"
+	o.Organisation = "myorg"
+
+	values := o.PlaceholderValues("github.com", "myorg")
+	assert.NotContains(t, values, "REPLACE_ME_DOCKER_REGISTRY", "an unknown docker registry should not be replaced")
+
+	err := os.WriteFile(filepath.Join(o.Dir, "values.yaml"), []byte("image: REPLACE_ME_DOCKER_REGISTRY/REPLACE_ME_DOCKER_REGISTRY_ORG/REPLACE_ME_APP_NAME\n"), 0600)
+	require.NoError(t, err, "failed to write values.yaml")
+
+	err = o.ReplacePlaceholders("github.com", "myorg")
+	require.NoError(t, err, "failed to replace placeholders")
+	assertFileEquals(t, filepath.Join(o.Dir, "values.yaml"), "image: REPLACE_ME_DOCKER_REGISTRY/myorg/myapp\n")
+}
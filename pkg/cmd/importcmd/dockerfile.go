@@ -0,0 +1,572 @@
+package importcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/yamls"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// DockerfileSettingsFileName the name of the file in the extensions directory of the dev environment git repository
+// which configures the generated Dockerfiles
+const DockerfileSettingsFileName = "dockerfile.yaml"
+
+// DockerfileSettings the team settings used to generate a Dockerfile if the source code and pack do not have one
+type DockerfileSettings struct {
+	// Registry the registry host and path to prefix the default base images with such as a mirror of docker hub
+	Registry string `json:"registry,omitempty"`
+	// BaseImages the base images to use instead of the defaults indexed by the language and stage such as
+	// go-build or maven-runtime. Any ${VERSION} is replaced with the detected language version
+	BaseImages map[string]string `json:"baseImages,omitempty"`
+}
+
+// Dockerfile the language details used to render a generated Dockerfile
+type Dockerfile struct {
+	// Language the language of the Dockerfile: maven, gradle, nodejs, python or go
+	Language string
+	// Version the detected version of the JDK, node, python or go
+	Version string
+	// BuildImage the base image of the build stage
+	BuildImage string
+	// RuntimeImage the base image of the runtime stage
+	RuntimeImage string
+	// Files the files which are copied before the rest of the source code to cache the dependencies
+	Files []string
+	// Install the command to download the dependencies
+	Install string
+	// Build the command to build the application
+	Build string
+	// Artifact the file or directory in /workspace of the build stage which is copied into /app of the runtime image
+	Artifact string
+	// Command the command to run the application
+	Command []string
+}
+
+// defaultDockerfileVersions the versions used if the source code does not specify one
+var defaultDockerfileVersions = map[string]string{
+	MAVEN:  "21",
+	GRADLE: "21",
+	NODEJS: "20",
+	PYTHON: "3.12",
+	GO:     "1.22",
+}
+
+// defaultBaseImages the default base images indexed by the language and stage
+var defaultBaseImages = map[string]string{
+	"maven-build":    "maven:3-eclipse-temurin-${VERSION}",
+	"maven-runtime":  "eclipse-temurin:${VERSION}-jre",
+	"gradle-build":   "gradle:8-jdk${VERSION}",
+	"gradle-runtime": "eclipse-temurin:${VERSION}-jre",
+	"nodejs-build":   "node:${VERSION}-slim",
+	"nodejs-runtime": "node:${VERSION}-slim",
+	"python-build":   "python:${VERSION}-slim",
+	"python-runtime": "python:${VERSION}-slim",
+	"go-build":       "golang:${VERSION}",
+	"go-runtime":     "gcr.io/distroless/static-debian12:nonroot",
+}
+
+// the .dockerignore entries used if the source code has no .dockerignore file
+var defaultDockerIgnores = map[string][]string{
+	MAVEN:  {"target"},
+	GRADLE: {".gradle", "build"},
+	NODEJS: {"node_modules", "npm-debug.log"},
+	PYTHON: {"__pycache__", "*.pyc", ".venv", "venv"},
+	GO:     {"bin"},
+}
+
+var dockerfileTemplates = map[string]string{
+	MAVEN: `FROM {{ .BuildImage }} AS build
+WORKDIR /workspace
+{{- if .Install }}
+COPY pom.xml .
+RUN {{ .Install }}
+{{- end }}
+COPY . .
+RUN {{ .Build }}
+
+FROM {{ .RuntimeImage }}
+RUN useradd --system --uid 1001 app
+WORKDIR /app
+COPY --from=build /workspace/{{ .Artifact }} /app/{{ .Artifact }}
+USER 1001
+EXPOSE 8080
+ENTRYPOINT {{ json .Command }}
+`,
+	GRADLE: `FROM {{ .BuildImage }} AS build
+WORKDIR /workspace
+COPY . .
+RUN {{ .Build }}
+
+FROM {{ .RuntimeImage }}
+RUN useradd --system --uid 1001 app
+WORKDIR /app
+COPY --from=build /workspace/app.jar /app/app.jar
+USER 1001
+EXPOSE 8080
+ENTRYPOINT {{ json .Command }}
+`,
+	NODEJS: `FROM {{ .BuildImage }} AS build
+WORKDIR /app
+COPY {{ join .Files " " }} ./
+RUN {{ .Install }}
+COPY . .
+{{- if .Build }}
+RUN {{ .Build }}
+{{- end }}
+
+FROM {{ .RuntimeImage }}
+ENV NODE_ENV=production PORT=8080
+WORKDIR /app
+COPY --from=build --chown=node:node /app /app
+USER node
+EXPOSE 8080
+CMD {{ json .Command }}
+`,
+	PYTHON: `FROM {{ .BuildImage }} AS build
+WORKDIR /app
+RUN python -m venv /opt/venv
+ENV PATH="/opt/venv/bin:$PATH"
+{{- if .Files }}
+COPY {{ join .Files " " }} ./
+RUN {{ .Install }}
+{{- end }}
+COPY . .
+{{- if .Build }}
+RUN {{ .Build }}
+{{- end }}
+
+FROM {{ .RuntimeImage }}
+RUN useradd --system --uid 1001 app
+WORKDIR /app
+COPY --from=build /opt/venv /opt/venv
+COPY --from=build --chown=1001 /app /app
+ENV PATH="/opt/venv/bin:$PATH" PYTHONUNBUFFERED=1 PORT=8080
+USER 1001
+EXPOSE 8080
+CMD {{ json .Command }}
+`,
+	GO: `FROM {{ .BuildImage }} AS build
+WORKDIR /src
+{{- if .Files }}
+COPY {{ join .Files " " }} ./
+RUN {{ .Install }}
+{{- end }}
+COPY . .
+RUN {{ .Build }}
+
+FROM {{ .RuntimeImage }}
+COPY --from=build /out/app /app
+USER nonroot:nonroot
+EXPOSE 8080
+ENTRYPOINT {{ json .Command }}
+`,
+}
+
+// loadDevDockerfileSettings loads the Dockerfile settings from the extensions directory of the dev environment git clone
+func (o *ImportOptions) loadDevDockerfileSettings(devEnvCloneDir string) error {
+	path := filepath.Join(devEnvCloneDir, "extensions", DockerfileSettingsFileName)
+	exists, err := files.FileExists(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if file exists %s", path)
+	}
+	if !exists {
+		return nil
+	}
+	o.dockerfileSettings = &DockerfileSettings{}
+	err = yamls.LoadFile(path, o.dockerfileSettings)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load Dockerfile settings file %s", path)
+	}
+	return nil
+}
+
+// generateMissingDockerfile generates a Dockerfile and .dockerignore for the language of the source code if the source
+// code and pack have no Dockerfile
+func (o *ImportOptions) generateMissingDockerfile(dir, pack string) error {
+	if o.DisableDockerfile {
+		return nil
+	}
+	path := filepath.Join(dir, "Dockerfile")
+	exists, err := files.FileExists(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if file exists %s", path)
+	}
+	if exists {
+		return nil
+	}
+	d, err := NewDockerfile(dir, pack, o.dockerfileSettings)
+	if err != nil {
+		return errors.Wrapf(err, "failed to detect the Dockerfile language")
+	}
+	if d == nil {
+		return nil
+	}
+	text, err := d.Render()
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(path, []byte(text), files.DefaultFileWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save file %s", path)
+	}
+	log.Logger().Infof("generated a %s Dockerfile as the pack %s does not have one", d.Language, pack)
+
+	// lets keep any existing .dockerignore file
+	path = filepath.Join(dir, ".dockerignore")
+	exists, err = files.FileExists(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if file exists %s", path)
+	}
+	if exists {
+		return nil
+	}
+	ignores := append([]string{".git", ".lighthouse", "charts"}, defaultDockerIgnores[d.Language]...)
+	err = os.WriteFile(path, []byte(strings.Join(ignores, "\n")+"\n"), files.DefaultFileWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save file %s", path)
+	}
+	return nil
+}
+
+// NewDockerfile returns the Dockerfile for the language and version of the source code in the directory or nil if
+// the language is not supported or the source code is a go library
+func NewDockerfile(dir, pack string, settings *DockerfileSettings) (*Dockerfile, error) {
+	language, err := dockerfileLanguage(dir, pack)
+	if err != nil || language == "" {
+		return nil, err
+	}
+	d := &Dockerfile{Language: language}
+	switch language {
+	case MAVEN:
+		err = d.configureMaven(dir)
+	case GRADLE:
+		err = d.configureGradle(dir)
+	case NODEJS:
+		err = d.configureNode(dir)
+	case PYTHON:
+		err = d.configurePython(dir)
+	case GO:
+		err = d.configureGo(dir)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to detect the %s project", language)
+	}
+	if d.Build == "" && d.Command == nil {
+		return nil, nil
+	}
+	if d.Version == "" {
+		d.Version = defaultDockerfileVersions[language]
+	}
+	if settings == nil {
+		settings = &DockerfileSettings{}
+	}
+	d.BuildImage = settings.baseImage(language+"-build", d.Version)
+	d.RuntimeImage = settings.baseImage(language+"-runtime", d.Version)
+	return d, nil
+}
+
+// Render renders the Dockerfile
+func (d *Dockerfile) Render() (string, error) {
+	funcs := template.FuncMap{
+		"join": strings.Join,
+		"json": func(values []string) (string, error) {
+			data, err := json.Marshal(values)
+			return string(data), err
+		},
+	}
+	tmpl, err := template.New(d.Language).Funcs(funcs).Parse(dockerfileTemplates[d.Language])
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse the %s Dockerfile template", d.Language)
+	}
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, d)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to render the %s Dockerfile template", d.Language)
+	}
+	return buf.String(), nil
+}
+
+// baseImage returns the base image for the language stage with the version
+func (s *DockerfileSettings) baseImage(name, version string) string {
+	image := s.BaseImages[name]
+	if image == "" {
+		image = defaultBaseImages[name]
+		if s.Registry != "" {
+			image = strings.TrimSuffix(s.Registry, "/") + "/" + image
+		}
+	}
+	return strings.ReplaceAll(image, "${VERSION}", version)
+}
+
+// dockerfileLanguage returns the language of the build files in the directory preferring gradle for gradle packs
+func dockerfileLanguage(dir, pack string) (string, error) {
+	exists := func(names ...string) (bool, error) {
+		for _, name := range names {
+			flag, err := files.FileExists(filepath.Join(dir, name))
+			if err != nil || flag {
+				return flag, err
+			}
+		}
+		return false, nil
+	}
+	languages := []struct {
+		name  string
+		files []string
+	}{
+		{name: MAVEN, files: []string{"pom.xml"}},
+		{name: GRADLE, files: GradleBuildFiles},
+		{name: NODEJS, files: []string{"package.json"}},
+		{name: GO, files: []string{"go.mod", "go.work"}},
+	}
+	if strings.HasPrefix(pack, GRADLE) {
+		languages[0], languages[1] = languages[1], languages[0]
+	}
+	for _, l := range languages {
+		flag, err := exists(l.files...)
+		if err != nil {
+			return "", err
+		}
+		if flag {
+			return l.name, nil
+		}
+	}
+	flag, err := HasPythonProjectFile(dir)
+	if err != nil || !flag {
+		return "", err
+	}
+	return PYTHON, nil
+}
+
+func (d *Dockerfile) configureMaven(dir string) error {
+	d.Install = "mvn -B -q dependency:go-offline"
+	targetDir := "target"
+	quarkus := false
+	m, err := LoadPom(filepath.Join(dir, "pom.xml"))
+	if err == nil {
+		for _, f := range m.Frameworks() {
+			if f.Name == QUARKUS {
+				quarkus = true
+			}
+		}
+		reactor := m.Reactor()
+		for _, r := range reactor {
+			d.Version = r.JavaVersion()
+			if d.Version != "" {
+				break
+			}
+		}
+		if len(reactor) > 1 {
+			// the modules of a reactor depend on each other so their dependencies can only be resolved by the build
+			d.Install = ""
+			app := mavenApplicationProject(reactor)
+			if app != nil {
+				rel, err := filepath.Rel(dir, filepath.Dir(app.Path))
+				if err == nil && rel != "." {
+					targetDir = filepath.ToSlash(rel) + "/target"
+				}
+			}
+		}
+	}
+	if quarkus {
+		// quarkus packages the application as a fast-jar directory whose jar does not contain the dependencies
+		d.Build = "mvn -B package -DskipTests && cp -r " + targetDir + "/quarkus-app /workspace/quarkus-app"
+		d.Artifact = "quarkus-app/"
+		d.Command = []string{"java", "-jar", "/app/quarkus-app/quarkus-run.jar"}
+		return nil
+	}
+	d.Build = "mvn -B package -DskipTests && cp $(ls " + targetDir + "/*.jar | grep -v -e sources -e javadoc | head -n 1) /workspace/app.jar"
+	d.Artifact = "app.jar"
+	d.Command = []string{"java", "-jar", "/app/app.jar"}
+	return nil
+}
+
+// executableJarPlugins the maven plugins which package an application as an executable jar
+var executableJarPlugins = []string{
+	"spring-boot-maven-plugin",
+	"quarkus-maven-plugin",
+	"micronaut-maven-plugin",
+	"maven-shade-plugin",
+	"maven-assembly-plugin",
+}
+
+// mavenApplicationProject returns the project of the reactor which packages the application preferring projects which
+// build an executable jar and then the last jar project as it usually depends on the others
+func mavenApplicationProject(reactor []*PomModel) *PomModel {
+	var answer *PomModel
+	for _, r := range reactor {
+		if r.Packaging() != "jar" {
+			continue
+		}
+		for _, plugin := range r.Project.Plugins {
+			if stringhelpers.StringArrayIndex(executableJarPlugins, r.Resolve(plugin.ArtifactID)) >= 0 {
+				return r
+			}
+		}
+		answer = r
+	}
+	return answer
+}
+
+func (d *Dockerfile) configureGradle(dir string) error {
+	var err error
+	d.Version, err = GradleJavaVersion(dir)
+	if err != nil {
+		return err
+	}
+	gradle := "gradle"
+	exists, err := files.FileExists(filepath.Join(dir, "gradlew"))
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if file exists %s", filepath.Join(dir, "gradlew"))
+	}
+	if exists {
+		gradle = "./gradlew"
+	}
+	d.Build = gradle + " build -x test --no-daemon && cp $(ls build/libs/*.jar | grep -v plain | head -n 1) /workspace/app.jar"
+	d.Command = []string{"java", "-jar", "/app/app.jar"}
+	return nil
+}
+
+func (d *Dockerfile) configureNode(dir string) error {
+	path := filepath.Join(dir, "package.json")
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read file %s", path)
+	}
+	p := &PackageJSON{}
+	err = json.Unmarshal(b, p)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse file %s", path)
+	}
+	d.Version = nodeMajorVersion.FindString(p.Engines["node"])
+	packageManager, err := NodePackageManager(dir, p)
+	if err != nil {
+		return err
+	}
+
+	d.Files = []string{"package.json"}
+	lockFile := ""
+	for _, l := range nodeLockFiles {
+		if l.packageManager != packageManager {
+			continue
+		}
+		exists, err := files.FileExists(filepath.Join(dir, l.file))
+		if err != nil {
+			return errors.Wrapf(err, "failed to check if file exists %s", filepath.Join(dir, l.file))
+		}
+		if exists {
+			lockFile = l.file
+			d.Files = append(d.Files, lockFile)
+			break
+		}
+	}
+	switch packageManager {
+	case NPM:
+		d.Install = "npm install"
+		if lockFile != "" {
+			d.Install = "npm ci"
+		}
+	default:
+		d.Install = "corepack enable && " + packageManager + " install"
+		if lockFile != "" {
+			d.Install += " --frozen-lockfile"
+		}
+	}
+	if p.Scripts["build"] != "" {
+		d.Build = packageManager + " run build"
+	}
+	switch {
+	case p.Scripts["start"] != "":
+		d.Command = []string{packageManager, "start"}
+	case p.Main != "":
+		d.Command = []string{"node", p.Main}
+	default:
+		d.Command = []string{"node", "index.js"}
+	}
+	return nil
+}
+
+func (d *Dockerfile) configurePython(dir string) error {
+	p, err := LoadPythonProject(dir)
+	if err != nil {
+		return err
+	}
+	d.Version = p.Version
+
+	has := func(name string) (bool, error) {
+		return files.FileExists(filepath.Join(dir, name))
+	}
+	requirements, err := has("requirements.txt")
+	if err != nil {
+		return err
+	}
+	pipfile, err := has("Pipfile")
+	if err != nil {
+		return err
+	}
+	pyproject, err := has("pyproject.toml")
+	if err != nil {
+		return err
+	}
+	switch {
+	case requirements:
+		d.Files = []string{"requirements.txt"}
+		d.Install = "pip install --no-cache-dir -r requirements.txt"
+	case pipfile:
+		d.Files = []string{"Pipfile*"}
+		d.Install = "pip install --no-cache-dir pipenv && pipenv install --system --deploy"
+	case pyproject:
+		d.Build = "pip install --no-cache-dir ."
+	}
+
+	main := "main.py"
+	for _, name := range []string{"main.py", "app.py", "manage.py"} {
+		exists, err := has(name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			main = name
+			break
+		}
+	}
+	switch {
+	case main == "manage.py":
+		d.Command = []string{"python", main, "runserver", "0.0.0.0:8080"}
+	case p.Dependencies["fastapi"] && p.Dependencies["uvicorn"]:
+		d.Command = []string{"uvicorn", strings.TrimSuffix(main, ".py") + ":app", "--host", "0.0.0.0", "--port", "8080"}
+	default:
+		d.Command = []string{"python", main}
+	}
+	return nil
+}
+
+func (d *Dockerfile) configureGo(dir string) error {
+	m, err := LoadGoModule(dir)
+	if err != nil || m == nil {
+		return err
+	}
+	if m.IsLibrary() {
+		// libraries are released without a container image
+		return nil
+	}
+	d.Version = m.Version()
+	if !m.Workspace {
+		d.Files = []string{"go.*"}
+		d.Install = "go mod download"
+	}
+	pkg := "."
+	if m.MainPackage != "." {
+		pkg = "./" + filepath.ToSlash(m.MainPackage)
+	}
+	d.Build = "CGO_ENABLED=0 go build -trimpath -ldflags=\"-s -w\" -o /out/app " + pkg
+	d.Command = []string{"/app"}
+	return nil
+}
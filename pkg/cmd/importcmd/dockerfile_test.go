@@ -0,0 +1,183 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDockerfile(t *testing.T) {
+	testCases := []struct {
+		name       string
+		pack       string
+		files      map[string]string
+		settings   *importcmd.DockerfileSettings
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name: "maven",
+			pack: "maven-java17",
+			files: map[string]string{
+				"pom.xml": `<project><modelVersion>4.0.0</modelVersion><groupId>a</groupId><artifactId>b</artifactId><version>1</version>
+<properties><maven.compiler.release>17</maven.compiler.release></properties></project>`,
+			},
+			expected: []string{
+				"FROM maven:3-eclipse-temurin-17 AS build\n",
+				"RUN mvn -B -q dependency:go-offline\n",
+				"FROM eclipse-temurin:17-jre\n",
+				"USER 1001\n",
+				`ENTRYPOINT ["java","-jar","/app/app.jar"]`,
+			},
+		},
+		{
+			name: "maven-multi-module",
+			pack: "maven-java17",
+			files: map[string]string{
+				"pom.xml": `<project><modelVersion>4.0.0</modelVersion><groupId>a</groupId><artifactId>parent</artifactId><version>1</version>
+<packaging>pom</packaging>
+<properties><maven.compiler.release>17</maven.compiler.release></properties>
+<modules><module>app</module><module>core</module></modules></project>`,
+				"app/pom.xml": `<project><modelVersion>4.0.0</modelVersion><parent><groupId>a</groupId><artifactId>parent</artifactId><version>1</version></parent>
+<artifactId>app</artifactId>
+<dependencies><dependency><groupId>a</groupId><artifactId>core</artifactId><version>1</version></dependency></dependencies>
+<build><plugins><plugin><groupId>org.springframework.boot</groupId><artifactId>spring-boot-maven-plugin</artifactId></plugin></plugins></build></project>`,
+				"core/pom.xml": `<project><modelVersion>4.0.0</modelVersion><parent><groupId>a</groupId><artifactId>parent</artifactId><version>1</version></parent>
+<artifactId>core</artifactId></project>`,
+			},
+			expected: []string{
+				"FROM maven:3-eclipse-temurin-17 AS build\n",
+				"WORKDIR /workspace\nCOPY . .\n",
+				"RUN mvn -B package -DskipTests && cp $(ls app/target/*.jar | grep -v -e sources -e javadoc | head -n 1) /workspace/app.jar\n",
+			},
+			unexpected: []string{
+				"dependency:go-offline",
+			},
+		},
+		{
+			name: "quarkus",
+			pack: "maven-quarkus",
+			files: map[string]string{
+				"pom.xml": `<project><modelVersion>4.0.0</modelVersion><groupId>a</groupId><artifactId>b</artifactId><version>1</version>
+<properties><maven.compiler.release>17</maven.compiler.release></properties>
+<build><plugins><plugin><groupId>io.quarkus.platform</groupId><artifactId>quarkus-maven-plugin</artifactId></plugin></plugins></build></project>`,
+			},
+			expected: []string{
+				"RUN mvn -B package -DskipTests && cp -r target/quarkus-app /workspace/quarkus-app\n",
+				"COPY --from=build /workspace/quarkus-app/ /app/quarkus-app/\n",
+				`ENTRYPOINT ["java","-jar","/app/quarkus-app/quarkus-run.jar"]`,
+			},
+			unexpected: []string{
+				"app.jar",
+			},
+		},
+		{
+			name: "gradle",
+			pack: "gradle",
+			files: map[string]string{
+				"build.gradle": "java {\n  toolchain {\n    languageVersion = JavaLanguageVersion.of(11)\n  }\n}\n",
+				"gradlew":      "#!/bin/sh\n",
+			},
+			settings: &importcmd.DockerfileSettings{Registry: "mirror.example.com/"},
+			expected: []string{
+				"FROM mirror.example.com/gradle:8-jdk11 AS build\n",
+				"RUN ./gradlew build -x test --no-daemon",
+				"FROM mirror.example.com/eclipse-temurin:11-jre\n",
+			},
+		},
+		{
+			name: "node",
+			pack: "nodejs18-yarn",
+			files: map[string]string{
+				"package.json": `{"engines": {"node": ">=18"}, "scripts": {"build": "tsc", "start": "node dist/index.js"}}`,
+				"yarn.lock":    "",
+			},
+			expected: []string{
+				"FROM node:18-slim AS build\n",
+				"COPY package.json yarn.lock ./\n",
+				"RUN corepack enable && yarn install --frozen-lockfile\n",
+				"RUN yarn run build\n",
+				"USER node\n",
+				`CMD ["yarn","start"]`,
+			},
+		},
+		{
+			name: "python",
+			pack: "python",
+			files: map[string]string{
+				".python-version":  "3.11\n",
+				"requirements.txt": "fastapi\nuvicorn\n",
+				"app.py":           "",
+			},
+			settings: &importcmd.DockerfileSettings{
+				BaseImages: map[string]string{"python-runtime": "registry.example.com/python:${VERSION}-distroless"},
+			},
+			expected: []string{
+				"FROM python:3.11-slim AS build\n",
+				"RUN pip install --no-cache-dir -r requirements.txt\n",
+				"FROM registry.example.com/python:3.11-distroless\n",
+				"USER 1001\n",
+				`CMD ["uvicorn","app:app","--host","0.0.0.0","--port","8080"]`,
+			},
+		},
+		{
+			name: "go",
+			pack: "go",
+			files: map[string]string{
+				"go.mod":            "module example.com/myapp\n\ngo 1.21\n",
+				"cmd/myapp/main.go": "package main\n\nfunc main() {}\n",
+			},
+			expected: []string{
+				"FROM golang:1.21 AS build\n",
+				"COPY go.* ./\nRUN go mod download\n",
+				`RUN CGO_ENABLED=0 go build -trimpath -ldflags="-s -w" -o /out/app ./cmd/myapp`,
+				"FROM gcr.io/distroless/static-debian12:nonroot\n",
+				"USER nonroot:nonroot\n",
+			},
+		},
+		{
+			name: "go-library",
+			pack: "go",
+			files: map[string]string{
+				"go.mod": "module example.com/mylib\n\ngo 1.21\n",
+				"lib.go": "package mylib\n",
+			},
+		},
+		{
+			name: "unknown",
+			pack: "docker",
+			files: map[string]string{
+				"README.md": "# hello\n",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestFiles(t, dir, tc.files)
+
+			d, err := importcmd.NewDockerfile(dir, tc.pack, tc.settings)
+			require.NoError(t, err, "failed to create the Dockerfile")
+			if len(tc.expected) == 0 {
+				assert.Nil(t, d, "should not generate a Dockerfile")
+				return
+			}
+			require.NotNil(t, d, "should generate a Dockerfile")
+
+			text, err := d.Render()
+			require.NoError(t, err, "failed to render the Dockerfile")
+			for _, e := range tc.expected {
+				assert.Contains(t, text, e)
+			}
+			for _, e := range tc.unexpected {
+				assert.NotContains(t, text, e)
+			}
+		})
+	}
+}
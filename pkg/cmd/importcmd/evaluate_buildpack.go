@@ -46,6 +46,7 @@ func (o *ImportOptions) EvaluateBuildPack(devEnvCloneDir, jenkinsfile string) er
 		CustomDraftPack: o.Pack,
 		Jenkinsfile:     jenkinsfile,
 		InitialisedGit:  o.InitialisedGit,
+		Out:             o.progressOut(),
 	}
 	o.Pack, err = o.InvokeDraftPack(args)
 	if err != nil {
@@ -53,7 +54,7 @@ func (o *ImportOptions) EvaluateBuildPack(devEnvCloneDir, jenkinsfile string) er
 	}
 
 	// let's rename the chart to be the same as our app name
-	err = o.renameChartToMatchAppName()
+	err = o.RenameChartToMatchAppName()
 	if err != nil {
 		return err
 	}
@@ -65,23 +66,9 @@ func (o *ImportOptions) EvaluateBuildPack(devEnvCloneDir, jenkinsfile string) er
 		}
 	}
 
-	gitServerName := ""
-	if o.gitInfo != nil {
-		gitServerName = o.gitInfo.Host
-	}
-	gitServerURL := o.ScmFactory.GitServerURL
-	if gitServerName == "" {
-		if gitServerURL == "" {
-			return errors.Errorf("no git server URL")
-		}
-		u, err := url.Parse(gitServerURL)
-		if err != nil {
-			return errors.Wrapf(err, "failed to parse git server URL %s", gitServerURL)
-		}
-		gitServerName = u.Host
-	}
-	if gitServerName == "" {
-		return errors.Errorf("no git server name")
+	gitServerName, err := o.getGitServerName()
+	if err != nil {
+		return err
 	}
 
 	if o.Organisation == "" {
@@ -106,6 +93,16 @@ func (o *ImportOptions) EvaluateBuildPack(devEnvCloneDir, jenkinsfile string) er
 		return err
 	}
 
+	err = o.ApplyDeployOptions()
+	if err != nil {
+		return errors.Wrapf(err, "failed to apply the deploy options to the chart")
+	}
+
+	err = o.VerifyChart()
+	if err != nil {
+		return err
+	}
+
 	// Create Prow owners file
 	err = o.CreateProwOwnersFile()
 	if err != nil {
@@ -118,6 +115,28 @@ func (o *ImportOptions) EvaluateBuildPack(devEnvCloneDir, jenkinsfile string) er
 	return nil
 }
 
+func (o *ImportOptions) getGitServerName() (string, error) {
+	gitServerName := ""
+	if o.gitInfo != nil {
+		gitServerName = o.gitInfo.Host
+	}
+	gitServerURL := o.ScmFactory.GitServerURL
+	if gitServerName == "" {
+		if gitServerURL == "" {
+			return "", errors.Errorf("no git server URL")
+		}
+		u, err := url.Parse(gitServerURL)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to parse git server URL %s", gitServerURL)
+		}
+		gitServerName = u.Host
+	}
+	if gitServerName == "" {
+		return "", errors.Errorf("no git server name")
+	}
+	return gitServerName, nil
+}
+
 func (o *ImportOptions) getDockerRegistryOrg() string {
 	dockerRegistryOrg := o.DockerRegistryOrg
 	if dockerRegistryOrg == "" {
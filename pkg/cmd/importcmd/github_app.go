@@ -0,0 +1,159 @@
+package importcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/scmhelpers"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/jenkins-x/lighthouse-client/pkg/util"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultGithubAppName the default name of the GitHub App used by lighthouse
+	DefaultGithubAppName = "jenkins-x"
+
+	// lighthouseDeploymentPrefix the prefix of the names of the lighthouse deployments
+	lighthouseDeploymentPrefix = "lighthouse"
+)
+
+// IsGitHubAppMode returns true if lighthouse uses a GitHub App to access the git provider rather than a bot user.
+//
+// This is true if --github-app is specified or if a lighthouse deployment in the dev namespace is configured with
+// a GitHub App secret directory
+func (o *ImportOptions) IsGitHubAppMode() (bool, error) {
+	if o.GithubAppMode {
+		return true, nil
+	}
+	if o.githubAppMode != nil {
+		return *o.githubAppMode, nil
+	}
+	answer, err := o.detectGitHubAppMode()
+	if err != nil {
+		return false, err
+	}
+	o.githubAppMode = &answer
+	return answer, nil
+}
+
+// detectGitHubAppMode detects if any of the lighthouse deployments are configured to use a GitHub App
+func (o *ImportOptions) detectGitHubAppMode() (bool, error) {
+	if o.ScmFactory.GitKind != "" && o.ScmFactory.GitKind != "github" {
+		return false, nil
+	}
+	if o.KubeClient == nil {
+		return false, nil
+	}
+	ctx := context.Background()
+	list, err := o.KubeClient.AppsV1().Deployments(o.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to list Deployments in namespace %s", o.Namespace)
+	}
+	for i := range list.Items {
+		d := &list.Items[i]
+		if !strings.HasPrefix(d.Name, lighthouseDeploymentPrefix) {
+			continue
+		}
+		for j := range d.Spec.Template.Spec.Containers {
+			for _, e := range d.Spec.Template.Spec.Containers[j].Env {
+				if e.Name == util.GitHubAppSecretDirEnvVar && e.Value != "" {
+					log.Logger().Debugf("lighthouse deployment %s uses a GitHub App", d.Name)
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// EnsureGithubAppInstalled checks that the GitHub App is installed on the given repository. If it is not, or the
+// installation cannot be verified, the user is guided to install it.
+//
+// The newRepository flag indicates the repository has not been created yet so that only an installation on all of the
+// repositories of the owner can be detected
+func (o *ImportOptions) EnsureGithubAppInstalled(owner, repoName string, newRepository bool) (bool, error) {
+	if o.GithubAppInstalled {
+		return true, nil
+	}
+	fullName := scm.Join(owner, repoName)
+	installation, err := o.findGithubAppInstallation(owner, repoName, newRepository)
+	if err != nil {
+		log.Logger().Debugf("could not verify if the GitHub App is installed on %s: %s", fullName, err.Error())
+	} else if installation != nil {
+		log.Logger().Infof("the GitHub App is installed on %s", termcolor.ColorInfo(fullName))
+		o.GithubAppInstalled = true
+		return true, nil
+	}
+
+	installURL := o.GithubAppInstallURL()
+	log.Logger().Infof("the GitHub App needs to be installed on %s so that lighthouse can access the repository and receive its webhooks", termcolor.ColorInfo(fullName))
+	log.Logger().Infof("you can install it via: %s", termcolor.ColorInfo(installURL))
+	if o.BatchMode {
+		if err == nil {
+			log.Logger().Warnf("the GitHub App is not installed on %s so pipelines will not be triggered until it is", fullName)
+		}
+		return false, nil
+	}
+
+	installed, err := o.Input.Confirm(fmt.Sprintf("Is the GitHub App installed on %s?", fullName), true, "Please install the GitHub App on the repository before continuing so that lighthouse can trigger its pipelines")
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to confirm the GitHub App installation")
+	}
+	if !installed {
+		return false, errors.Errorf("the GitHub App must be installed on %s via %s", fullName, installURL)
+	}
+	o.GithubAppInstalled = true
+	return true, nil
+}
+
+// GithubAppInstallURL returns the URL to install the GitHub App on the git server
+func (o *ImportOptions) GithubAppInstallURL() string {
+	name := o.GithubAppName
+	if name == "" {
+		name = DefaultGithubAppName
+	}
+	serverURL := strings.TrimSuffix(o.ScmFactory.GitServerURL, "/")
+	if serverURL == "" {
+		serverURL = "https://github.com"
+	}
+	if serverURL == "https://github.com" {
+		return fmt.Sprintf("%s/apps/%s/installations/new", serverURL, name)
+	}
+	// GitHub Enterprise serves the apps under a different path
+	return fmt.Sprintf("%s/github-apps/%s/installations/new", serverURL, name)
+}
+
+// findGithubAppInstallation returns the installation of the GitHub App for the repository or nil if it is not installed
+func (o *ImportOptions) findGithubAppInstallation(owner, repoName string, newRepository bool) (*scm.Installation, error) {
+	scmClient := o.ScmFactory.ScmClient
+	if scmClient == nil || scmClient.Apps == nil {
+		return nil, errors.Errorf("the git provider does not support querying GitHub App installations")
+	}
+	ctx := context.Background()
+	if !newRepository {
+		installation, _, err := scmClient.Apps.GetRepositoryInstallation(ctx, scm.Join(owner, repoName))
+		if err == nil {
+			return installation, nil
+		}
+		if !scmhelpers.IsScmNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to find the GitHub App installation for %s", scm.Join(owner, repoName))
+		}
+	}
+
+	installation, _, err := scmClient.Apps.GetOrganisationInstallation(ctx, owner)
+	if err != nil {
+		if scmhelpers.IsScmNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to find the GitHub App installation for %s", owner)
+	}
+	if installation != nil && installation.RepositorySelection == "all" {
+		return installation, nil
+	}
+	return nil, nil
+}
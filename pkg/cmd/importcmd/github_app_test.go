@@ -0,0 +1,84 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/testimports"
+	"github.com/jenkins-x/lighthouse-client/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsGitHubAppMode(t *testing.T) {
+	o := &importcmd.ImportOptions{}
+	testimports.SetFakeClients(t, o, false)
+
+	githubAppMode, err := o.IsGitHubAppMode()
+	require.NoError(t, err, "failed to detect GitHub App mode")
+	assert.False(t, githubAppMode, "should not be in GitHub App mode without a lighthouse GitHub App deployment")
+
+	o = &importcmd.ImportOptions{}
+	testimports.SetFakeClients(t, o, false)
+	_, err = o.KubeClient.AppsV1().Deployments(o.Namespace).Create(context.Background(), &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "lighthouse-webhooks",
+			Namespace: o.Namespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "lighthouse-webhooks",
+							Env: []corev1.EnvVar{
+								{
+									Name:  util.GitHubAppSecretDirEnvVar,
+									Value: "/secrets/githubapp/tokens",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err, "failed to create the lighthouse deployment")
+
+	githubAppMode, err = o.IsGitHubAppMode()
+	require.NoError(t, err, "failed to detect GitHub App mode")
+	assert.True(t, githubAppMode, "should detect GitHub App mode from the lighthouse deployment")
+
+	// the bot user should not be added as a collaborator in GitHub App mode
+	err = o.AddAndAcceptCollaborator(true)
+	require.NoError(t, err, "failed to add and accept collaborator")
+}
+
+func TestEnsureGithubAppInstalled(t *testing.T) {
+	o := &importcmd.ImportOptions{}
+	testimports.SetFakeClients(t, o, false)
+	o.BatchMode = true
+	o.GithubAppMode = true
+	o.ScmFactory.GitServerURL = "https://github.com"
+
+	installed, err := o.EnsureGithubAppInstalled("myorg", "myrepo", true)
+	require.NoError(t, err, "should not fail in batch mode")
+	assert.False(t, installed, "should not be installed")
+	assert.Equal(t, "https://github.com/apps/jenkins-x/installations/new", o.GithubAppInstallURL())
+
+	o.GithubAppName = "my-app"
+	o.ScmFactory.GitServerURL = "https://github.mycompany.com"
+	assert.Equal(t, "https://github.mycompany.com/github-apps/my-app/installations/new", o.GithubAppInstallURL())
+
+	o.GithubAppInstalled = true
+	installed, err = o.EnsureGithubAppInstalled("myorg", "myrepo", true)
+	require.NoError(t, err, "failed to check the GitHub App installation")
+	assert.True(t, installed, "should be installed")
+}
@@ -0,0 +1,236 @@
+package importcmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	GO        = "go"
+	GOLIBRARY = "go-library"
+)
+
+var (
+	goDirective        = regexp.MustCompile(`(?m)^\s*go\s+(\d+)\.(\d+)`)
+	goToolchain        = regexp.MustCompile(`(?m)^\s*toolchain\s+go(\d+)\.(\d+)`)
+	goPackageMain      = regexp.MustCompile(`(?m)^package\s+main\b`)
+	goWorkUseDirective = regexp.MustCompile(`(?m)^\s*use\s+([^\s(]+)`)
+	goWorkUseBlock     = regexp.MustCompile(`(?ms)^\s*use\s*\((.*?)\)`)
+)
+
+// GoModule the details of a go module or go.work workspace used to pick the pack
+type GoModule struct {
+	// GoVersion the major and minor version of the go directive like 1.22
+	GoVersion string
+	// Toolchain the major and minor version of the toolchain directive like 1.22
+	Toolchain string
+	// Workspace true if there is a go.work file
+	Workspace bool
+	// Modules the directories of the modules used by the workspace
+	Modules []string
+	// HasMain true if there is a main package so that the module builds a binary
+	HasMain bool
+	// MainPackage the relative directory of the first main package like . or cmd/myapp
+	MainPackage string
+}
+
+// Version returns the toolchain version if specified otherwise the go version
+func (m *GoModule) Version() string {
+	if m.Toolchain != "" {
+		return m.Toolchain
+	}
+	return m.GoVersion
+}
+
+// IsLibrary returns true if the module has no main package so it does not need a container image or chart
+func (m *GoModule) IsLibrary() bool {
+	return !m.HasMain
+}
+
+// LoadGoModule loads the go module details from the go.work or go.mod files in the directory or returns nil if there
+// are none
+func LoadGoModule(dir string) (*GoModule, error) {
+	work, err := readOptionalFile(filepath.Join(dir, "go.work"))
+	if err != nil {
+		return nil, err
+	}
+	mod, err := readOptionalFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	if work == "" && mod == "" {
+		return nil, nil
+	}
+	m := &GoModule{}
+	if work != "" {
+		m.Workspace = true
+		for _, block := range goWorkUseBlock.FindAllStringSubmatch(work, -1) {
+			for _, line := range strings.Split(block[1], "\n") {
+				line = strings.TrimSpace(strings.Split(line, "//")[0])
+				if line != "" {
+					m.Modules = append(m.Modules, line)
+				}
+			}
+		}
+		for _, use := range goWorkUseDirective.FindAllStringSubmatch(work, -1) {
+			m.Modules = append(m.Modules, use[1])
+		}
+	}
+
+	// the go.work directives take precedence as they are used for the build
+	for _, s := range []string{work, mod} {
+		if m.GoVersion == "" {
+			if v := goDirective.FindStringSubmatch(s); v != nil {
+				m.GoVersion = v[1] + "." + v[2]
+			}
+		}
+		if m.Toolchain == "" {
+			if v := goToolchain.FindStringSubmatch(s); v != nil {
+				m.Toolchain = v[1] + "." + v[2]
+			}
+		}
+	}
+
+	m.MainPackage, err = findGoMainPackage(dir)
+	if err != nil {
+		return m, err
+	}
+	m.HasMain = m.MainPackage != ""
+	return m, nil
+}
+
+// findGoMainPackage returns the relative directory of the first go source file in a main package in the directory tree
+// or an empty string if there is none
+func findGoMainPackage(dir string) (string, error) {
+	found := ""
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != dir && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "vendor" || name == "testdata" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read file %s", path)
+		}
+		if goPackageMain.Match(b) {
+			found, err = filepath.Rel(dir, filepath.Dir(path))
+			if err != nil {
+				return errors.Wrapf(err, "failed to find the relative path of %s", path)
+			}
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find main packages in %s", dir)
+	}
+	return found, nil
+}
+
+// GoFlavour returns the go pack to use for the go.mod or go.work files in the directory.
+//
+// Library only modules use the go-library pack if the catalog has it then a goX.Y pack is used for the version of the
+// toolchain or go directive, otherwise the go pack
+func GoFlavour(packsDir, dir string) (string, error) {
+	m, err := LoadGoModule(dir)
+	if err != nil || m == nil {
+		return GO, err
+	}
+	packExists := func(pack string) bool {
+		exists, _ := files.DirExists(filepath.Join(packsDir, pack))
+		return exists
+	}
+	if m.IsLibrary() && packExists(GOLIBRARY) {
+		return GOLIBRARY, nil
+	}
+	version := m.Version()
+	if version != "" {
+		pack := GO + version
+		if packExists(pack) {
+			return pack, nil
+		}
+	}
+	return GO, nil
+}
+
+// PickGoLibraryPack returns the pack to use for a go module without a main package. The pipelines of most go packs
+// build an image and deploy a chart so leaving out the chart and Dockerfile would break them. Instead the release
+// only go-library pack of the catalog is offered which replaces the chart, Dockerfile and pipelines of the pack.
+// If the catalog has no go-library pack the pack is used as it is
+func (o *ImportOptions) PickGoLibraryPack(dir, packsDir, pack string, explicit bool) (string, error) {
+	library, err := GoLibraryPack(dir, packsDir, pack)
+	if err != nil || library == pack {
+		return pack, err
+	}
+	if o.BatchMode {
+		if explicit {
+			return pack, nil
+		}
+	} else {
+		flag, err := o.Input.Confirm(fmt.Sprintf("The go module has no main package. Would you like to use the release only %s pack instead of %s?", GOLIBRARY, pack), true,
+			"libraries do not need a container image or helm chart so they can be released by tagging the git repository")
+		if err != nil {
+			return pack, errors.Wrapf(err, "failed to confirm the go library import")
+		}
+		if !flag {
+			return pack, nil
+		}
+	}
+	log.Logger().Infof("the go module has no main package so using the release only pack %s", termcolor.ColorInfo(GOLIBRARY))
+	return GOLIBRARY, nil
+}
+
+// GoLibraryPack returns the release only go-library pack if the source code is a go module without a main package
+// and the pack builds an image or deploys a chart, otherwise the pack
+func GoLibraryPack(dir, packsDir, pack string) (string, error) {
+	if pack == GOLIBRARY {
+		return pack, nil
+	}
+	m, err := LoadGoModule(dir)
+	if err != nil || m == nil || !m.IsLibrary() {
+		return pack, err
+	}
+	packDir := filepath.Join(packsDir, pack)
+	exists, err := files.DirExists(filepath.Join(packDir, ChartsDir))
+	if err != nil {
+		return pack, errors.Wrapf(err, "failed to check if dir exists %s", filepath.Join(packDir, ChartsDir))
+	}
+	if !exists {
+		exists, err = files.FileExists(filepath.Join(packDir, "Dockerfile"))
+		if err != nil {
+			return pack, errors.Wrapf(err, "failed to check if file exists %s", filepath.Join(packDir, "Dockerfile"))
+		}
+	}
+	if !exists {
+		return pack, nil
+	}
+	libraryDir := filepath.Join(packsDir, GOLIBRARY)
+	exists, err = files.DirExists(libraryDir)
+	if err != nil {
+		return pack, errors.Wrapf(err, "failed to check if dir exists %s", libraryDir)
+	}
+	if !exists {
+		log.Logger().Warnf("the go module has no main package but the pipeline catalog has no %s pack so the pipelines of the pack %s will build an image and deploy a chart",
+			GOLIBRARY, termcolor.ColorInfo(pack))
+		return pack, nil
+	}
+	return GOLIBRARY, nil
+}
@@ -0,0 +1,182 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoFlavour(t *testing.T) {
+	t.Parallel()
+
+	mainFile := "package main\n\nfunc main() {}\n"
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		packs    []string
+		expected string
+	}{
+		{
+			name: "default",
+			files: map[string]string{
+				"go.mod":  "module github.com/myorg/myapp\n",
+				"main.go": mainFile,
+			},
+			packs:    []string{importcmd.GOLIBRARY},
+			expected: importcmd.GO,
+		},
+		{
+			name: "go version",
+			files: map[string]string{
+				"go.mod":          "module github.com/myorg/myapp\n\ngo 1.22.1\n",
+				"cmd/app/main.go": mainFile,
+			},
+			packs:    []string{"go1.21", "go1.22"},
+			expected: "go1.22",
+		},
+		{
+			name: "toolchain",
+			files: map[string]string{
+				"go.mod":  "module github.com/myorg/myapp\n\ngo 1.21\n\ntoolchain go1.22.3\n",
+				"main.go": mainFile,
+			},
+			packs:    []string{"go1.21", "go1.22"},
+			expected: "go1.22",
+		},
+		{
+			name: "go version missing from catalog",
+			files: map[string]string{
+				"go.mod":  "module github.com/myorg/myapp\n\ngo 1.23\n",
+				"main.go": mainFile,
+			},
+			packs:    []string{"go1.22"},
+			expected: importcmd.GO,
+		},
+		{
+			name: "library",
+			files: map[string]string{
+				"go.mod":           "module github.com/myorg/mylib\n\ngo 1.22\n",
+				"lib.go":           "package mylib\n",
+				"lib_test.go":      "package main\n",
+				"testdata/main.go": mainFile,
+			},
+			packs:    []string{importcmd.GOLIBRARY, "go1.22"},
+			expected: importcmd.GOLIBRARY,
+		},
+		{
+			name: "library missing from catalog",
+			files: map[string]string{
+				"go.mod": "module github.com/myorg/mylib\n\ngo 1.22\n",
+				"lib.go": "package mylib\n",
+			},
+			packs:    []string{"go1.22"},
+			expected: "go1.22",
+		},
+		{
+			name: "workspace",
+			files: map[string]string{
+				"go.work":            "go 1.22\n\nuse (\n\t./api\n\t./server // the server\n)\n",
+				"api/go.mod":         "module github.com/myorg/api\n\ngo 1.21\n",
+				"api/api.go":         "package api\n",
+				"server/go.mod":      "module github.com/myorg/server\n\ngo 1.21\n",
+				"server/cmd/main.go": mainFile,
+			},
+			packs:    []string{importcmd.GOLIBRARY, "go1.21", "go1.22"},
+			expected: "go1.22",
+		},
+	}
+	for _, tc := range testCases {
+		dir := t.TempDir()
+		for name, content := range tc.files {
+			path := filepath.Join(dir, name)
+			err := os.MkdirAll(filepath.Dir(path), 0700)
+			require.NoError(t, err, "failed to create dir for %s for %s", name, tc.name)
+			err = os.WriteFile(path, []byte(content), 0600)
+			require.NoError(t, err, "failed to write %s for %s", name, tc.name)
+		}
+		packsDir := t.TempDir()
+		for _, pack := range tc.packs {
+			err := os.Mkdir(filepath.Join(packsDir, pack), 0700)
+			require.NoError(t, err, "failed to create pack %s for %s", pack, tc.name)
+		}
+
+		flavour, err := importcmd.GoFlavour(packsDir, dir)
+		require.NoError(t, err, "failed to detect flavour for %s", tc.name)
+		assert.Equal(t, tc.expected, flavour, "flavour for %s", tc.name)
+	}
+}
+
+func TestLoadGoModuleWorkspace(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "go.work"), []byte("go 1.22\n\ntoolchain go1.23.0\n\nuse ./tools\n\nuse (\n\t./api\n)\n"), 0600)
+	require.NoError(t, err, "failed to write go.work")
+
+	m, err := importcmd.LoadGoModule(dir)
+	require.NoError(t, err, "failed to load go module")
+	require.NotNil(t, m, "no go module found")
+
+	assert.True(t, m.Workspace, "workspace")
+	assert.ElementsMatch(t, []string{"./api", "./tools"}, m.Modules, "modules")
+	assert.Equal(t, "1.22", m.GoVersion, "go version")
+	assert.Equal(t, "1.23", m.Version(), "version")
+	assert.True(t, m.IsLibrary(), "library")
+}
+
+func TestPickGoLibraryPack(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		packs    map[string]string
+		explicit bool
+		expected string
+	}{
+		{
+			name:     "library",
+			files:    map[string]string{"go.mod": "module github.com/myorg/mylib\n", "lib.go": "package mylib\n"},
+			packs:    map[string]string{"go/charts/go/Chart.yaml": "name: go\n", "go-library/.lighthouse/jenkins-x/release.yaml": "kind: PipelineRun\n"},
+			expected: importcmd.GOLIBRARY,
+		},
+		{
+			name:     "explicit pack",
+			files:    map[string]string{"go.mod": "module github.com/myorg/mylib\n", "lib.go": "package mylib\n"},
+			packs:    map[string]string{"go/charts/go/Chart.yaml": "name: go\n", "go-library/.lighthouse/jenkins-x/release.yaml": "kind: PipelineRun\n"},
+			explicit: true,
+			expected: importcmd.GO,
+		},
+		{
+			name:     "no release only pack",
+			files:    map[string]string{"go.mod": "module github.com/myorg/mylib\n", "lib.go": "package mylib\n"},
+			packs:    map[string]string{"go/charts/go/Chart.yaml": "name: go\n", "go/Dockerfile": "FROM scratch\n"},
+			expected: importcmd.GO,
+		},
+		{
+			name:     "application",
+			files:    map[string]string{"go.mod": "module github.com/myorg/myapp\n", "main.go": "package main\n\nfunc main() {}\n"},
+			packs:    map[string]string{"go/charts/go/Chart.yaml": "name: go\n", "go-library/.lighthouse/jenkins-x/release.yaml": "kind: PipelineRun\n"},
+			expected: importcmd.GO,
+		},
+	}
+	for _, tc := range testCases {
+		dir := t.TempDir()
+		writeTestFiles(t, dir, tc.files)
+		packsDir := t.TempDir()
+		writeTestFiles(t, packsDir, tc.packs)
+
+		o := &importcmd.ImportOptions{}
+		o.BatchMode = true
+		pack, err := o.PickGoLibraryPack(dir, packsDir, importcmd.GO, tc.explicit)
+		require.NoError(t, err, "failed to pick pack for %s", tc.name)
+		assert.Equal(t, tc.expected, pack, "pack for %s", tc.name)
+	}
+}
@@ -0,0 +1,242 @@
+package importcmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/yamls"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// GoTemplateFileName the name of the file in the .jx directory of a pack or quickstart which enables rendering
+	// go templates
+	GoTemplateFileName = "gotemplate.yaml"
+
+	// GoTemplateExtension the extension of files which are rendered and then saved without the extension
+	GoTemplateExtension = ".gotmpl"
+)
+
+// GoTemplateConfig the configuration of the go templates in a pack or quickstart.
+//
+// If the configuration is added by the pack or quickstart every file ending in .gotmpl is also rendered and the
+// configuration file is removed once rendered. A configuration which is already in the source code only renders
+// the files it lists and is kept so that existing .gotmpl files are left alone
+type GoTemplateConfig struct {
+	// Files the globs of the files relative to the project to render. Globs without a / match the file name in any
+	// directory
+	Files []string `json:"files,omitempty"`
+	// Exclude the globs of the files which should not be rendered
+	Exclude []string `json:"exclude,omitempty"`
+	// LeftDelim the left delimiter of the templates which defaults to {{
+	LeftDelim string `json:"leftDelim,omitempty"`
+	// RightDelim the right delimiter of the templates which defaults to }}
+	RightDelim string `json:"rightDelim,omitempty"`
+	// Values the extra values available to the templates as .Values
+	Values map[string]interface{} `json:"values,omitempty"`
+}
+
+// GoTemplateContext the values available to the go templates
+type GoTemplateContext struct {
+	// AppName the name of the application
+	AppName string
+	// Organisation the git organisation
+	Organisation string
+	// GitServer the host name of the git server
+	GitServer string
+	// DockerRegistry the docker registry host
+	DockerRegistry string
+	// DockerRegistryOrg the docker registry organisation
+	DockerRegistryOrg string
+	// DeployKind the kind of deployment
+	DeployKind string
+	// Canary whether canary rollouts are enabled
+	Canary bool
+	// HPA whether the Horizontal Pod Autoscaler is enabled
+	HPA bool
+	// TeamSettings the team settings of the dev Environment
+	TeamSettings *v1.TeamSettings
+	// Values the extra values of the go template configuration
+	Values map[string]interface{}
+}
+
+// goTemplateRenderer renders the go templates of a directory
+type goTemplateRenderer struct {
+	dir     string
+	config  *GoTemplateConfig
+	context *GoTemplateContext
+	added   bool
+}
+
+// LoadGoTemplateConfig loads the .jx/gotemplate.yaml file in the directory or returns nil if it does not exist
+func LoadGoTemplateConfig(dir string) (*GoTemplateConfig, error) {
+	path := filepath.Join(dir, ".jx", GoTemplateFileName)
+	exists, err := files.FileExists(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check if file exists %s", path)
+	}
+	if !exists {
+		return nil, nil
+	}
+	config := &GoTemplateConfig{}
+	err = yamls.LoadFile(path, config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load go template configuration file %s", path)
+	}
+	return config, nil
+}
+
+// GoTemplateContext returns the values available to go templates
+func (o *ImportOptions) GoTemplateContext(gitServerName, dockerRegistryOrg string) *GoTemplateContext {
+	ctx := &GoTemplateContext{
+		AppName:           strings.ToLower(o.AppName),
+		Organisation:      o.Organisation,
+		GitServer:         strings.ToLower(gitServerName),
+		DockerRegistry:    strings.ToLower(o.DockerRegistry),
+		DockerRegistryOrg: strings.ToLower(dockerRegistryOrg),
+		DeployKind:        o.DeployKind,
+		Canary:            o.DeployOptions.Canary,
+		HPA:               o.DeployOptions.HPA,
+		TeamSettings:      &v1.TeamSettings{},
+		Values:            map[string]interface{}{},
+	}
+	if o.DevEnv != nil {
+		ctx.TeamSettings = &o.DevEnv.Spec.TeamSettings
+	}
+	return ctx
+}
+
+// newGoTemplateRenderer returns a renderer if the directory contains a .jx/gotemplate.yaml file or nil
+func (o *ImportOptions) newGoTemplateRenderer(gitServerName, dockerRegistryOrg string) (*goTemplateRenderer, error) {
+	config, err := LoadGoTemplateConfig(o.Dir)
+	if err != nil || config == nil {
+		return nil, err
+	}
+	ctx := o.GoTemplateContext(gitServerName, dockerRegistryOrg)
+	for k, v := range config.Values {
+		ctx.Values[k] = v
+	}
+	return &goTemplateRenderer{
+		dir:     o.Dir,
+		config:  config,
+		context: ctx,
+		added:   o.AddedGoTemplateConfig,
+	}, nil
+}
+
+// matches returns true if the file should be rendered
+func (r *goTemplateRenderer) matches(relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	if relPath == ".jx/"+GoTemplateFileName {
+		return false
+	}
+	if matchesGlobs(r.config.Exclude, relPath) {
+		return false
+	}
+	return (r.added && strings.HasSuffix(relPath, GoTemplateExtension)) || matchesGlobs(r.config.Files, relPath)
+}
+
+// render renders the file if it matches returning the path of the rendered file
+func (r *goTemplateRenderer) render(path string) (string, error) {
+	relPath, err := filepath.Rel(r.dir, path)
+	if err != nil {
+		return path, err
+	}
+	if !r.matches(relPath) {
+		return path, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return path, errors.Wrapf(err, "failed to stat file %s", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return path, errors.Wrapf(err, "failed to read file %s", path)
+	}
+	t, err := template.New(relPath).Delims(r.config.LeftDelim, r.config.RightDelim).Funcs(goTemplateFuncs).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return path, errors.Wrapf(err, "failed to parse go template %s", relPath)
+	}
+	var buf bytes.Buffer
+	err = t.Execute(&buf, r.context)
+	if err != nil {
+		return path, errors.Wrapf(err, "failed to render go template %s", relPath)
+	}
+
+	output := path
+	if strings.HasSuffix(path, GoTemplateExtension) {
+		output = strings.TrimSuffix(path, GoTemplateExtension)
+		err = os.Remove(path)
+		if err != nil {
+			return path, errors.Wrapf(err, "failed to remove go template %s", path)
+		}
+	}
+	err = os.WriteFile(output, buf.Bytes(), info.Mode().Perm())
+	if err != nil {
+		return path, errors.Wrapf(err, "failed to save file %s", output)
+	}
+	return output, nil
+}
+
+// remove removes the go template configuration file added by the pack or quickstart as it is not needed once the
+// templates are rendered
+func (r *goTemplateRenderer) remove() error {
+	if !r.added {
+		return nil
+	}
+	path := filepath.Join(r.dir, ".jx", GoTemplateFileName)
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to remove %s", path)
+	}
+	return nil
+}
+
+// matchesGlobs returns true if the slash separated path matches any of the globs. Globs without a / match the file name
+func matchesGlobs(globs []string, relPath string) bool {
+	name := relPath[strings.LastIndex(relPath, "/")+1:]
+	for _, glob := range globs {
+		glob = strings.TrimPrefix(glob, "/")
+		text := relPath
+		if !strings.Contains(glob, "/") {
+			text = name
+		}
+		if m, _ := filepath.Match(glob, text); m {
+			return true
+		}
+	}
+	return false
+}
+
+var goTemplateFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+	"replace": func(old, replacement, s string) string {
+		return strings.ReplaceAll(s, old, replacement)
+	},
+	"default": func(defaultValue, value interface{}) interface{} {
+		if value == nil || fmt.Sprint(value) == "" {
+			return defaultValue
+		}
+		return value
+	},
+	"quote": func(value interface{}) string {
+		return strconv.Quote(fmt.Sprint(value))
+	},
+	"toYaml": func(value interface{}) (string, error) {
+		data, err := yaml.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(string(data), "\n"), nil
+	},
+}
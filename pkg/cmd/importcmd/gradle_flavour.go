@@ -0,0 +1,211 @@
+package importcmd
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/pkg/errors"
+)
+
+const (
+	GRADLE     = "gradle"
+	ANDROID    = "android"
+	QUARKUS    = "quarkus"
+	MICRONAUT  = "micronaut"
+	SPRINGBOOT = "spring-boot"
+)
+
+// GradleBuildFiles the names of the gradle build files using the Groovy and Kotlin DSLs
+var GradleBuildFiles = []string{"build.gradle", "build.gradle.kts"}
+
+var (
+	gradleAndroidPlugin   = regexp.MustCompile(`["']com\.android\.(application|library)["']`)
+	gradleQuarkusPlugin   = regexp.MustCompile(`["']io\.quarkus["']`)
+	gradleMicronautPlugin = regexp.MustCompile(`["']io\.micronaut\.[a-z.-]+["']`)
+	gradleSpringPlugin    = regexp.MustCompile(`["']org\.springframework\.boot["']`)
+
+	// the patterns to find the java version in order of precedence
+	gradleJavaVersionPatterns = []*regexp.Regexp{
+		// java { toolchain { languageVersion = JavaLanguageVersion.of(17) } }
+		regexp.MustCompile(`JavaLanguageVersion\.of\(\s*["']?(\d+)["']?\s*\)`),
+		// kotlin { jvmToolchain(17) }
+		regexp.MustCompile(`jvmToolchain\(\s*(\d+)\s*\)`),
+		regexp.MustCompile(`sourceCompatibility\s*=\s*JavaVersion\.VERSION_([\d_]+)`),
+		regexp.MustCompile(`sourceCompatibility\s*=\s*["']?([\d.]+)["']?`),
+		regexp.MustCompile(`targetCompatibility\s*=\s*JavaVersion\.VERSION_([\d_]+)`),
+		regexp.MustCompile(`targetCompatibility\s*=\s*["']?([\d.]+)["']?`),
+	}
+
+	// the properties in gradle.properties which are commonly used for the java version
+	gradleJavaVersionProperties = []string{"javaVersion", "java.version", "sourceCompatibility", "targetCompatibility"}
+)
+
+// HasGradleBuildFile returns true if there is a gradle build file in the directory
+func HasGradleBuildFile(dir string) (bool, error) {
+	for _, name := range GradleBuildFiles {
+		path := filepath.Join(dir, name)
+		exists, err := files.FileExists(path)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to check if file exists %s", path)
+		}
+		if exists {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GradleFlavour returns the gradle pack to use for the gradle build files in the directory.
+//
+// The gradle-android, gradle-quarkus, gradle-micronaut and gradle-spring-boot packs are used if the catalog has them then a gradle-javaNN pack is used
+// for the java version of the toolchain or source compatibility, otherwise the gradle pack
+func GradleFlavour(packsDir, dir string) (string, error) {
+	s, err := loadGradleBuildFiles(dir)
+	if err != nil {
+		return "", err
+	}
+
+	packExists := func(pack string) bool {
+		exists, _ := files.DirExists(filepath.Join(packsDir, pack))
+		return exists
+	}
+	var frameworks []string
+	if gradleAndroidPlugin.MatchString(s) {
+		frameworks = append(frameworks, ANDROID)
+	}
+	if gradleQuarkusPlugin.MatchString(s) {
+		frameworks = append(frameworks, QUARKUS)
+	}
+	if gradleMicronautPlugin.MatchString(s) {
+		frameworks = append(frameworks, MICRONAUT)
+	}
+	if gradleSpringPlugin.MatchString(s) {
+		frameworks = append(frameworks, SPRINGBOOT)
+	}
+	for _, framework := range frameworks {
+		// the bare framework packs build with maven so only use the gradle variants
+		pack := GRADLE + "-" + framework
+		if packExists(pack) {
+			return pack, nil
+		}
+	}
+
+	version, err := gradleProjectJavaVersion(dir, s)
+	if err != nil {
+		return "", err
+	}
+	if version != "" {
+		pack := GRADLE + "-java" + version
+		if packExists(pack) {
+			return pack, nil
+		}
+	}
+	return GRADLE, nil
+}
+
+// GradleJavaVersion returns the major java version of the gradle build files or gradle.properties in the directory
+func GradleJavaVersion(dir string) (string, error) {
+	s, err := loadGradleBuildFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	return gradleProjectJavaVersion(dir, s)
+}
+
+// loadGradleBuildFiles returns the contents of the gradle build files in the directory
+func loadGradleBuildFiles(dir string) (string, error) {
+	s := ""
+	for _, name := range GradleBuildFiles {
+		path := filepath.Join(dir, name)
+		exists, err := files.FileExists(path)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to check if file exists %s", path)
+		}
+		if !exists {
+			continue
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to read file %s", path)
+		}
+		s += string(b) + "\n"
+	}
+	return s, nil
+}
+
+// gradleProjectJavaVersion returns the java version of the build files falling back to the gradle.properties
+func gradleProjectJavaVersion(dir, s string) (string, error) {
+	version := gradleJavaVersion(s)
+	if version != "" {
+		return version, nil
+	}
+	properties, err := loadGradleProperties(filepath.Join(dir, "gradle.properties"))
+	if err != nil {
+		return "", err
+	}
+	for _, name := range gradleJavaVersionProperties {
+		version = normaliseJavaVersion(properties[name])
+		if version != "" {
+			return version, nil
+		}
+	}
+	return "", nil
+}
+
+// gradleJavaVersion returns the java version of the toolchain or source compatibility in the build file
+func gradleJavaVersion(s string) string {
+	for _, re := range gradleJavaVersionPatterns {
+		matches := re.FindStringSubmatch(s)
+		if matches != nil {
+			version := normaliseJavaVersion(matches[1])
+			if version != "" {
+				return version
+			}
+		}
+	}
+	return ""
+}
+
+// normaliseJavaVersion converts versions like 1.8, 1_8 or 17 to the major version 8 or 17
+func normaliseJavaVersion(version string) string {
+	version = strings.ReplaceAll(strings.TrimSpace(version), "_", ".")
+	version = strings.TrimPrefix(version, "1.")
+	version = strings.Split(version, ".")[0]
+	for _, r := range version {
+		if r < '0' || r > '9' {
+			return ""
+		}
+	}
+	return version
+}
+
+// loadGradleProperties loads the simple key=value properties of a gradle.properties file if it exists
+func loadGradleProperties(path string) (map[string]string, error) {
+	answer := map[string]string{}
+	exists, err := files.FileExists(path)
+	if err != nil {
+		return answer, errors.Wrapf(err, "failed to check if file exists %s", path)
+	}
+	if !exists {
+		return answer, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return answer, errors.Wrapf(err, "failed to read file %s", path)
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		i := strings.IndexAny(line, "=:")
+		if i <= 0 {
+			continue
+		}
+		answer[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+	}
+	return answer, nil
+}
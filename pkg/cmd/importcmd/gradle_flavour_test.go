@@ -0,0 +1,121 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGradleFlavour(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		packs    []string
+		expected string
+	}{
+		{
+			name:     "default",
+			files:    map[string]string{"build.gradle": "plugins { id 'java' }"},
+			expected: importcmd.GRADLE,
+		},
+		{
+			name:     "groovy toolchain",
+			files:    map[string]string{"build.gradle": "java {\n  toolchain {\n    languageVersion = JavaLanguageVersion.of(17)\n  }\n}"},
+			packs:    []string{"gradle-java11", "gradle-java17"},
+			expected: "gradle-java17",
+		},
+		{
+			name:     "kotlin jvm toolchain",
+			files:    map[string]string{"build.gradle.kts": "kotlin {\n  jvmToolchain(21)\n}"},
+			packs:    []string{"gradle-java21"},
+			expected: "gradle-java21",
+		},
+		{
+			name:     "source compatibility",
+			files:    map[string]string{"build.gradle": "sourceCompatibility = '1.8'"},
+			packs:    []string{"gradle-java8"},
+			expected: "gradle-java8",
+		},
+		{
+			name:     "kotlin java version",
+			files:    map[string]string{"build.gradle.kts": "java.sourceCompatibility = JavaVersion.VERSION_11"},
+			packs:    []string{"gradle-java11"},
+			expected: "gradle-java11",
+		},
+		{
+			name:     "java version missing from catalog",
+			files:    map[string]string{"build.gradle": "sourceCompatibility = 17"},
+			expected: importcmd.GRADLE,
+		},
+		{
+			name: "gradle properties",
+			files: map[string]string{
+				"build.gradle":      "sourceCompatibility = javaVersion",
+				"gradle.properties": "# the java version\njavaVersion=17\n",
+			},
+			packs:    []string{"gradle-java17"},
+			expected: "gradle-java17",
+		},
+		{
+			name:     "spring boot",
+			files:    map[string]string{"build.gradle.kts": "plugins {\n  id(\"org.springframework.boot\") version \"3.1.0\"\n}\njava.sourceCompatibility = JavaVersion.VERSION_17"},
+			packs:    []string{"gradle-spring-boot", "gradle-java17"},
+			expected: "gradle-spring-boot",
+		},
+		{
+			name:     "quarkus",
+			files:    map[string]string{"build.gradle": "plugins {\n  id 'io.quarkus'\n}"},
+			packs:    []string{"gradle-quarkus", importcmd.QUARKUS},
+			expected: "gradle-quarkus",
+		},
+		{
+			name:     "micronaut",
+			files:    map[string]string{"build.gradle.kts": "plugins {\n  id(\"io.micronaut.application\") version \"4.0.0\"\n}"},
+			packs:    []string{"gradle-micronaut"},
+			expected: "gradle-micronaut",
+		},
+		{
+			name:     "android",
+			files:    map[string]string{"build.gradle": "apply plugin: 'com.android.application'"},
+			packs:    []string{"gradle-android"},
+			expected: "gradle-android",
+		},
+		{
+			name:     "framework missing from catalog",
+			files:    map[string]string{"build.gradle": "plugins {\n  id 'io.quarkus'\n}\nsourceCompatibility = 17"},
+			packs:    []string{"gradle-java17"},
+			expected: "gradle-java17",
+		},
+		{
+			name:     "maven framework pack",
+			files:    map[string]string{"build.gradle.kts": "plugins {\n  id(\"org.springframework.boot\") version \"3.1.0\"\n}"},
+			packs:    []string{importcmd.SPRINGBOOT},
+			expected: importcmd.GRADLE,
+		},
+	}
+	for _, tc := range testCases {
+		dir := t.TempDir()
+		for name, content := range tc.files {
+			err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600)
+			require.NoError(t, err, "failed to write %s for %s", name, tc.name)
+		}
+		packsDir := t.TempDir()
+		for _, pack := range tc.packs {
+			err := os.Mkdir(filepath.Join(packsDir, pack), 0700)
+			require.NoError(t, err, "failed to create pack %s for %s", pack, tc.name)
+		}
+
+		flavour, err := importcmd.GradleFlavour(packsDir, dir)
+		require.NoError(t, err, "failed to detect flavour for %s", tc.name)
+		assert.Equal(t, tc.expected, flavour, "flavour for %s", tc.name)
+	}
+}
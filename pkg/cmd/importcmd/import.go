@@ -3,9 +3,11 @@ package importcmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,7 +16,6 @@ import (
 	"github.com/denormal/go-gitignore"
 	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/common"
 	"github.com/jenkins-x-plugins/jx-project/pkg/constants"
-	"github.com/jenkins-x-plugins/jx-project/pkg/maven"
 	"github.com/jenkins-x/go-scm/scm"
 	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx-api/v4/pkg/client/clientset/versioned"
@@ -51,21 +52,26 @@ type CallbackFn func() error
 type ImportOptions struct {
 	options.BaseOptions
 
-	Args             []string
-	RepoURL          string
-	GitProviderURL   string
-	DiscoveredGitURL string
-	Dir              string
-	Organisation     string
-	Repository       string
+	Args              []string
+	ProjectConfigFile string
+	RepoURL           string
+	GitProviderURL    string
+	DiscoveredGitURL  string
+	Dir               string
+	Organisation      string
+	Repository        string
 	// Credentials                        string
 	AppName      string
 	SelectFilter string
+	PlanFormat   string
+	OutputFormat string
 	Jenkinsfile  string
 	// BranchPattern                      string
 	ImportGitCommitMessage string
 	Pack                   string
 	DockerRegistryOrg      string
+	DockerRegistry         string
+	SetPlaceholders        []string
 	DeployKind             string
 	SchedulerName          string
 	GitConfDir             string
@@ -77,16 +83,24 @@ type ImportOptions struct {
 	OperatorNamespace                  string
 	BootSecretName                     string
 	PipelineCatalogDir                 string
+	MonorepoDir                        string
 	DisableMaven                       bool
+	GithubAppName                      string
 	GithubAppInstalled                 bool
+	GithubAppMode                      bool
 	GitHub                             bool
 	DryRun                             bool
 	SelectAll                          bool
+	Monorepo                           bool
+	Resume                             bool
 	DisableBuildPack                   bool
+	DisableDockerfile                  bool
+	SkipValidation                     bool
 	DisableWebhooks                    bool
 	DisableDotGitSearch                bool
 	DisableStartPipeline               bool
 	InitialisedGit                     bool
+	AddedGoTemplateConfig              bool
 	WaitForSourceRepositoryPullRequest bool
 	NoDevPullRequest                   bool
 	IgnoreExistingRepository           bool
@@ -103,12 +117,19 @@ type ImportOptions struct {
 	CommandRunner                      cmdrunner.CommandRunner
 	DevEnv                             *v1.Environment
 	BootScmClient                      *scm.Client
+	Cmd                                *cobra.Command
 
 	OnCompleteCallback    func() error
 	PostDraftPackCallback CallbackFn
 	gitInfo               *giturl.GitRepository
 	Destination           ImportDestination
 	reporter              ImportReporter
+	state                 *ImportState
+	projectConfig         *ProjectConfig
+	parentProjectConfig   *ProjectConfig
+	githubAppMode         *bool
+	devPlaceholders       map[string]string
+	dockerfileSettings    *DockerfileSettings
 	PackFilter            func(*Pack)
 	// env customization
 	EnvName     string
@@ -124,8 +145,6 @@ type ImportOptions struct {
 }
 
 const (
-	updateBotMavenPluginVersion = "RELEASE"
-
 	JenkinsfileName = "Jenkinsfile"
 )
 
@@ -138,6 +157,13 @@ var (
 
 	    You can specify the git URL as an argument.
 
+		The import options can also be specified in a '.jx/project.yaml' file in the repository (or a file specified via '--config') so that imports are reproducible. Each option uses the first value found in this order:
+
+		* command line flags which are specified
+		* the project configuration file
+		* the team settings of the dev Environment
+		* the default values of the command line flags
+
 		For more documentation see: [https://jenkins-x.io/docs/using-jx/creating/import/](https://jenkins-x.io/docs/using-jx/creating/import/)
 
 `)
@@ -160,6 +186,15 @@ var (
 
         # Import all repositories from a GitHub organisation which contain the text foo
 		%s import --github --org myname --all --filter foo
+
+		# Import a monorepo creating a chart and pipelines for each service in the services directory
+		%s import --monorepo
+
+		# View what the import would do as YAML without changing anything
+		%s import --plan yaml
+
+		# Import the current folder writing the progress as newline delimited JSON events
+		%s import --batch-mode --output json
 		`)
 
 	deployKinds = []string{constants.DeployKindKnative, constants.DeployKindDefault}
@@ -179,7 +214,7 @@ func NewCmdImportAndOptions() (*cobra.Command, *ImportOptions) {
 		Use:     "import",
 		Short:   "Imports a local project or Git repository into Jenkins X",
 		Long:    importLong,
-		Example: fmt.Sprintf(importExample, common.BinaryName, common.BinaryName, common.BinaryName, common.BinaryName, common.BinaryName, common.BinaryName),
+		Example: fmt.Sprintf(importExample, common.BinaryName, common.BinaryName, common.BinaryName, common.BinaryName, common.BinaryName, common.BinaryName, common.BinaryName, common.BinaryName, common.BinaryName),
 		Run: func(_ *cobra.Command, _ []string) {
 			err := opts.Run()
 			helper.CheckErr(err)
@@ -188,6 +223,11 @@ func NewCmdImportAndOptions() (*cobra.Command, *ImportOptions) {
 	cmd.Flags().StringVarP(&opts.RepoURL, "url", "u", "", "The git clone URL to clone into the current directory and then import")
 	cmd.Flags().BoolVarP(&opts.GitHub, "github", "", false, "If you wish to pick the repositories from GitHub to import")
 	cmd.Flags().BoolVarP(&opts.SelectAll, "all", "", false, "If selecting projects to import from a Git provider this defaults to selecting them all")
+	cmd.Flags().StringVarP(&opts.SelectFilter, "filter", "", "", "If selecting projects to import from a Git provider this filters the list of repositories by name")
+	cmd.Flags().StringVarP(&opts.PlanFormat, "plan", "", "", "Outputs the plan of what the import would do in the given format (json or yaml) without changing anything")
+	cmd.Flags().BoolVarP(&opts.Monorepo, "monorepo", "", false, "Imports a monorepo applying a pipeline catalog pack to each service in a subdirectory of the --monorepo-dir directory")
+	cmd.Flags().StringVarP(&opts.MonorepoDir, "monorepo-dir", "", DefaultMonorepoDir, "The directory containing a subdirectory for each service when using --monorepo")
+	cmd.Flags().BoolVarP(&opts.Resume, "resume", "", false, "Resumes a previous import which failed part way through by skipping the steps recorded in the .jx/"+ImportStateFileName+" file")
 
 	opts.AddImportFlags(cmd, false)
 	return cmd, opts
@@ -200,17 +240,23 @@ func (o *ImportOptions) AddImportFlags(cmd *cobra.Command, createProject bool) {
 		}
 		return text
 	}
+	o.Cmd = cmd
 	cmd.Flags().StringVarP(&o.GitProviderURL, "git-provider-url", "", "", "Deprecated: please use --git-server")
+	cmd.Flags().StringVarP(&o.ProjectConfigFile, "config", "", "", "The project configuration file to use instead of the .jx/"+ProjectConfigFileName+" file in the repository. Flags which are specified take precedence over the configuration file which takes precedence over the team settings")
 	cmd.Flags().StringVarP(&o.Organisation, "org", "", "", "Specify the Git provider organisation to import the project into (if it is not already in one)")
 	cmd.Flags().StringVarP(&o.Dir, "dir", "", ".", "Specify the directory to import")
 	cmd.Flags().StringVarP(&o.PipelineCatalogDir, "pipeline-catalog-dir", "", "", "The pipeline catalog directory you want to use instead of the buildPackGitURL in the dev Environment Team settings. Generally only used for testing pipelines")
 	cmd.Flags().StringVarP(&o.Repository, "name", notCreateProject("n"), "", "Specify the Git repository name to import the project into (if it is not already in one)")
 	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Performs local changes to the repo but skips the import into Jenkins X")
 	cmd.Flags().BoolVarP(&o.DisableBuildPack, "no-pack", "", false, "Disable trying to default a Dockerfile and Helm Chart from the pipeline catalog pack")
+	cmd.Flags().BoolVarP(&o.SkipValidation, "skip-validation", "", false, "Skips validating the generated helm chart and the lighthouse triggers and pipelines before they are pushed")
+	cmd.Flags().BoolVarP(&o.DisableDockerfile, "no-dockerfile", "", false, "Disable generating a Dockerfile for the language of the source code if neither the source code nor the pack has one")
 	cmd.Flags().BoolVarP(&o.DisableMaven, "no-maven-fix", "", false, "Disable trying to fix existing pom.xml")
 	cmd.Flags().StringVarP(&o.ImportGitCommitMessage, "import-commit-message", "", "", "Specifies the initial commit message used when importing the project")
 	cmd.Flags().StringVarP(&o.Pack, "pack", "", "", "The name of the pipeline catalog pack to use. If none is specified it will be chosen based on matching the source code languages")
 	cmd.Flags().StringVarP(&o.DockerRegistryOrg, "docker-registry-org", "", "", "The name of the docker registry organisation to use. If not specified then the Git provider organisation will be used")
+	cmd.Flags().StringVarP(&o.DockerRegistry, "docker-registry", "", "", "The docker registry host used to replace the "+constants.PlaceHolderDockerRegistry+" placeholder. If not specified then the $DOCKER_REGISTRY environment variable or the docker registry of the cluster is used")
+	cmd.Flags().StringArrayVarP(&o.SetPlaceholders, "set-placeholder", "", nil, "Defines an extra placeholder to replace in the source code of the form KEY=VALUE. The "+constants.PlaceHolderPrefix+"_ prefix is added to the KEY if it is missing")
 	cmd.Flags().StringVarP(&o.OperatorNamespace, "operator-namespace", "", boot.GitOperatorNamespace, "The namespace where the git operator is installed")
 	cmd.Flags().StringVarP(&o.BootSecretName, "boot-secret-name", "", boot.SecretName, "The name of the boot secret")
 	cmd.Flags().StringVarP(&o.DeployKind, "deploy-kind", "", "", fmt.Sprintf("The kind of deployment to use for the project. Should be one of %s", strings.Join(deployKinds, ", ")))
@@ -224,6 +270,8 @@ func (o *ImportOptions) AddImportFlags(cmd *cobra.Command, createProject bool) {
 	cmd.Flags().BoolVarP(&o.WaitForSourceRepositoryPullRequest, "wait-for-pr", "", true, "waits for the Pull Request generated on the cluster environment git repository to merge")
 	cmd.Flags().BoolVarP(&o.NoDevPullRequest, "no-dev-pr", "", false, "disables generating a Pull Request on the cluster git repository")
 	cmd.Flags().BoolVarP(&o.DisableStartPipeline, "no-start", "", false, "disables starting a release pipeline when importing/creating a new project")
+	cmd.Flags().BoolVarP(&o.GithubAppMode, "github-app", "", false, "Indicates lighthouse uses a GitHub App rather than a bot user so no collaborator is added. If not specified it is detected from the lighthouse deployments")
+	cmd.Flags().StringVarP(&o.GithubAppName, "github-app-name", "", DefaultGithubAppName, "The name of the GitHub App used by lighthouse which is used to guide installing it on the repository")
 	cmd.Flags().BoolVarP(&o.IgnoreCollaborator, "no-collaborator", "", false, "disables checking if the bot user is a collaborator. Only used if you have an issue with your git provider and this functionality in go-scm")
 	cmd.Flags().DurationVarP(&o.PullRequestPollPeriod, "pr-poll-period", "", time.Second*20, "the time between polls of the Pull Request on the cluster environment git repository")
 	cmd.Flags().DurationVarP(&o.PullRequestPollTimeout, "pr-poll-timeout", "", time.Minute*20, "the maximum amount of time we wait for the Pull Request on the cluster environment git repository")
@@ -232,6 +280,7 @@ func (o *ImportOptions) AddImportFlags(cmd *cobra.Command, createProject bool) {
 	// FIXME parse enum and through what specified do not fit in enum
 	cmd.Flags().StringVar(&o.EnvStrategy, "env-strategy", "Never", "The promotion strategy of the environment to create (only used for env projects)")
 	cmd.Flags().BoolVarP(&o.NestedRepo, "nested-repo", "", false, "Specify if using nested repositories (in gitlab)")
+	cmd.Flags().StringVarP(&o.OutputFormat, "output", "", "", "The output format of the progress of the import. If json then newline delimited JSON events are written to stdout and the logs to stderr")
 	o.AddBaseFlags(cmd)
 	o.ScmFactory.AddFlags(cmd)
 
@@ -244,34 +293,24 @@ func (o *ImportOptions) Validate() error {
 	if err != nil {
 		return errors.Wrapf(err, "failed to validate base options")
 	}
-	if o.Input == nil {
-		o.Input = inputfactory.NewInput(&o.BaseOptions)
-	}
-
-	o.KubeClient, o.Namespace, err = kube.LazyCreateKubeClientAndNamespace(o.KubeClient, o.Namespace)
+	err = o.configureOutput()
 	if err != nil {
-		return errors.Wrapf(err, "failed to create the kube client")
+		return err
 	}
-	o.JXClient, err = jxclient.LazyCreateJXClient(o.JXClient)
+	_, err = parsePlaceholderArgs(o.SetPlaceholders)
 	if err != nil {
-		return errors.Wrapf(err, "failed to create the jx client")
+		return err
 	}
-	if o.CommandRunner == nil {
-		o.CommandRunner = cmdrunner.QuietCommandRunner
+	if o.DockerRegistry == "" {
+		o.DockerRegistry = os.Getenv("DOCKER_REGISTRY")
 	}
-
-	if o.DevEnv == nil {
-		o.DevEnv, err = jxenv.GetDevEnvironment(o.JXClient, o.Namespace)
-		if err != nil {
-			return errors.Wrapf(err, "failed to find the dev Environment")
-		}
+	if o.Input == nil {
+		o.Input = inputfactory.NewInput(&o.BaseOptions)
 	}
-	if o.DevEnv == nil {
-		extraMessage := ""
-		if o.Namespace != "jx" {
-			extraMessage = " Please run 'jx ns jx' to switch to the development namespace and retry this command"
-		}
-		return errors.Errorf("could not find the dev Environment in the namespace %s.%s", o.Namespace, extraMessage)
+
+	err = o.LoadDevEnvironment()
+	if err != nil {
+		return err
 	}
 
 	if o.ScmFactory.GitServerURL == "" && o.GitProviderURL != "" {
@@ -319,23 +358,80 @@ func (o *ImportOptions) Validate() error {
 	return nil
 }
 
+// LoadDevEnvironment lazily creates the kubernetes clients and finds the dev Environment
+func (o *ImportOptions) LoadDevEnvironment() error {
+	var err error
+	o.KubeClient, o.Namespace, err = kube.LazyCreateKubeClientAndNamespace(o.KubeClient, o.Namespace)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the kube client")
+	}
+	o.JXClient, err = jxclient.LazyCreateJXClient(o.JXClient)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the jx client")
+	}
+	if o.CommandRunner == nil {
+		o.CommandRunner = cmdrunner.QuietCommandRunner
+	}
+
+	if o.DevEnv == nil {
+		o.DevEnv, err = jxenv.GetDevEnvironment(o.JXClient, o.Namespace)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find the dev Environment")
+		}
+	}
+	if o.DevEnv == nil {
+		extraMessage := ""
+		if o.Namespace != "jx" {
+			extraMessage = " Please run 'jx ns jx' to switch to the development namespace and retry this command"
+		}
+		return errors.Errorf("could not find the dev Environment in the namespace %s.%s", o.Namespace, extraMessage)
+	}
+	return nil
+}
+
 // Run executes the command
 func (o *ImportOptions) Run() error {
+	err := o.run()
+	if !o.GitHub {
+		// each repository imported from an organisation reports its own result
+		o.GetReporter().Completed(o.repositoryFullName(), o.DiscoveredGitURL, err)
+	}
+	return err
+}
+
+func (o *ImportOptions) run() error {
 	err := o.Validate()
 	if err != nil {
 		return errors.Wrapf(err, "failed to validate options")
 	}
 
+	if o.GitHub {
+		err = o.loadAndApplyProjectConfig("")
+		if err != nil {
+			return err
+		}
+		err = o.DefaultsFromTeamSettings()
+		if err != nil {
+			return err
+		}
+		return o.ImportProjectsFromGitHub()
+	}
+	if o.PlanFormat != "" {
+		return o.RunPlan()
+	}
+
 	o.DiscoveredGitURL = o.RepoURL
 	if o.RepoURL == "" {
-		err = o.DiscoverGit()
+		err = o.DiscoverProject()
 		if err != nil {
 			return err
 		}
-
-		o.DiscoveredGitURL, err = gitdiscovery.FindGitURLFromDir(o.Dir, true)
+	} else {
+		// the configuration in the repository is only available after cloning so lets apply any --config file
+		// before deciding whether to clone
+		err = o.loadAndApplyProjectConfig("")
 		if err != nil {
-			return errors.Wrapf(err, "failed to discover the git URL")
+			return err
 		}
 	}
 	if o.DiscoveredGitURL != "" {
@@ -345,17 +441,6 @@ func (o *ImportOptions) Run() error {
 		}
 	}
 
-	err = o.DefaultsFromTeamSettings()
-	if err != nil {
-		return err
-	}
-
-	/* TODO
-	if o.GitHub {
-		return o.ImportProjectsFromGitHub()
-	}
-	*/
-
 	checkForJenkinsfile := o.Jenkinsfile == ""
 	shouldClone := checkForJenkinsfile || !o.DisableBuildPack
 
@@ -371,21 +456,31 @@ func (o *ImportOptions) Run() error {
 			}
 		}
 	}
-	if o.AppName == "" && o.gitInfo != nil {
-		o.Organisation = o.gitInfo.Organisation
-		o.AppName = o.gitInfo.Name
+	if o.RepoURL != "" && shouldClone && o.ProjectConfigFile == "" {
+		err = o.loadAndApplyProjectConfig(o.Dir)
+		if err != nil {
+			return err
+		}
 	}
-	if o.AppName == "" {
-		dir, err := filepath.Abs(o.Dir)
+	err = o.DefaultsFromTeamSettings()
+	if err != nil {
+		return err
+	}
+
+	if o.RepoURL == "" || shouldClone {
+		err = o.loadImportState()
+		if err != nil {
+			return errors.Wrapf(err, "failed to load the import state")
+		}
+		err = o.completeStep(StepDiscoverGit)
 		if err != nil {
 			return err
 		}
-		_, o.AppName = filepath.Split(dir)
 	}
-	if o.Repository == "" && o.NestedRepo {
-		o.Repository = o.AppName
+	err = o.defaultAppName()
+	if err != nil {
+		return err
 	}
-	o.AppName = naming.ToValidName(strings.ToLower(o.AppName))
 	jenkinsfile, err := o.HasJenkinsfile()
 	if err != nil {
 		return err
@@ -411,25 +506,45 @@ func (o *ImportOptions) Run() error {
 		}
 	}
 
-	if !o.DisableBuildPack {
-		g := filepath.Join(o.Dir, ".lighthouse", "*", "triggers.yaml")
-		matches, err := filepath.Glob(g)
-		if err != nil {
-			return errors.Wrapf(err, "failed to evaluate glob %s", g)
-		}
-		if len(matches) > 0 {
-			o.DisableBuildPack = true
+	if o.isStepCompleted(StepBuildPack) {
+		// the pack files were added by the previous import but not yet committed
+		o.DisableBuildPack = o.state.DisableBuildPack
+	} else {
+		if !o.DisableBuildPack {
+			g := filepath.Join(o.Dir, ".lighthouse", "*", "triggers.yaml")
+			matches, err := filepath.Glob(g)
+			if err != nil {
+				return errors.Wrapf(err, "failed to evaluate glob %s", g)
+			}
+			if len(matches) > 0 {
+				o.DisableBuildPack = true
+			}
 		}
-	}
 
-	if !o.DisableBuildPack {
-		err = o.EvaluateBuildPack(devEnvCloneDir, jenkinsfile)
+		if !o.DisableBuildPack {
+			if o.Monorepo {
+				err = o.EvaluateMonorepoBuildPacks(devEnvCloneDir)
+			} else {
+				err = o.EvaluateBuildPack(devEnvCloneDir, jenkinsfile)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if o.state != nil {
+			o.state.DisableBuildPack = o.DisableBuildPack
+		}
+		err = o.completeStep(StepBuildPack)
 		if err != nil {
 			return err
 		}
 	}
 
 	o.OnCompleteCallback = func() error {
+		err = o.validateLighthouse()
+		if err != nil {
+			return err
+		}
 		if !o.DisableBuildPack {
 			log.Logger().Infof("committing the pipeline catalog changes...")
 			_, err = gitclient.AddAndCommitFiles(o.Git(), o.Dir, "chore: Jenkins X build pack")
@@ -456,18 +571,30 @@ func (o *ImportOptions) Run() error {
 		return nil
 	}
 
-	newRepository := false
+	newRepository := o.state != nil && o.state.CreatedRepository
 	if o.DiscoveredGitURL == "" {
 		if !o.DryRun {
 			err = o.CreateNewRemoteRepository()
 			if err != nil {
 				if !o.DisableBuildPack {
-					log.Logger().Warn("Remote repository creation failed. In order to retry consider adding '--no-pack' option.")
+					log.Logger().Warn("Remote repository creation failed. In order to retry consider adding '--resume' or '--no-pack' option.")
 				}
 				return err
 			}
 			newRepository = true
 		}
+	} else if newRepository && !o.isStepCompleted(StepCreateRepository) {
+		// the previous import created the repository but failed to push to it
+		err = o.pushNewRepository(o.state.RepositoryURL)
+		if err != nil {
+			return err
+		}
+	}
+	if !o.DryRun {
+		err = o.completeStep(StepCreateRepository)
+		if err != nil {
+			return err
+		}
 	}
 	if o.DryRun {
 		shouldClone = false
@@ -480,16 +607,21 @@ func (o *ImportOptions) Run() error {
 		return nil
 	}
 
-	if !o.IgnoreCollaborator {
+	if !o.IgnoreCollaborator && !o.isStepCompleted(StepAddCollaborator) {
 		err = o.AddAndAcceptCollaborator(newRepository)
 		if err != nil {
 			return errors.Wrapf(err, "failed to add and accept collaborator")
 		}
+		err = o.completeStep(StepAddCollaborator)
+		if err != nil {
+			return err
+		}
 	}
 
 	gitURL := ""
+	var gitInfo *giturl.GitRepository
 	if o.DiscoveredGitURL != "" {
-		gitInfo, err := giturl.ParseGitURL(o.DiscoveredGitURL)
+		gitInfo, err = giturl.ParseGitURL(o.DiscoveredGitURL)
 		if err != nil {
 			return err
 		}
@@ -499,58 +631,40 @@ func (o *ImportOptions) Run() error {
 		return errors.Errorf("no git URL could be found")
 	}
 
-	/* TODO github app support
 	if !o.GithubAppInstalled {
 		githubAppMode, err := o.IsGitHubAppMode()
 		if err != nil {
 			return err
 		}
-
 		if githubAppMode {
-			githubApp := &github.GithubApp{
-				Factory: o.GetFactory(),
-			}
-
-			installed, err := githubApp.Install(o.Organisation, o.Repository, o.GetIOFileHandles(), false)
+			_, err = o.EnsureGithubAppInstalled(gitInfo.Organisation, gitInfo.Name, false)
 			if err != nil {
 				return err
 			}
-			o.GithubAppInstalled = installed
 		}
 	}
-	*/
 	return o.doImport()
 }
 
-// ImportProjectsFromGitHub import projects from github
-/** TODO
-func (o *ImportOptions) ImportProjectsFromGitHub() error {
-	repos, err := gits.PickRepositories(o.ScmClient, o.Organisation, "Which repositories do you want to import", o.SelectAll, o.SelectFilter, o.GetIOFileHandles())
-	if err != nil {
-		return err
+// defaultAppName defaults the app name from the git repository or directory name
+func (o *ImportOptions) defaultAppName() error {
+	if o.AppName == "" && o.gitInfo != nil {
+		o.Organisation = o.gitInfo.Organisation
+		o.AppName = o.gitInfo.Name
 	}
-
-	log.Logger().Info("Selected repositories")
-	for _, r := range repos {
-		o2 := ImportOptions{
-			CommonOptions: o.CommonOptions,
-			Dir:           o.Dir,
-			RepoURL:       r.CloneURL,
-			Organisation:  o.Organisation,
-			Repository:    r.Name,
-			//Jenkins:          o.Jenkins,
-			ScmClient:        o.ScmClient,
-			DisableBuildPack: o.DisableBuildPack,
-		}
-		log.Logger().Infof("Importing repository %s", termcolor.ColorInfo(r.Name))
-		err = o2.Run()
+	if o.AppName == "" {
+		dir, err := filepath.Abs(o.Dir)
 		if err != nil {
 			return err
 		}
+		_, o.AppName = filepath.Split(dir)
 	}
+	if o.Repository == "" && o.NestedRepo {
+		o.Repository = o.AppName
+	}
+	o.AppName = naming.ToValidName(strings.ToLower(o.AppName))
 	return nil
 }
-*/
 
 // GetReporter returns the reporter interface
 func (o *ImportOptions) GetReporter() ImportReporter {
@@ -560,6 +674,69 @@ func (o *ImportOptions) GetReporter() ImportReporter {
 	return o.reporter
 }
 
+// configureOutput sets up the reporter for the output format
+func (o *ImportOptions) configureOutput() error {
+	switch o.OutputFormat {
+	case "", "text":
+		return nil
+	case "json":
+		if o.PlanFormat != "" {
+			return options.InvalidOptionf("output", o.OutputFormat, "cannot be used with --plan")
+		}
+		if o.reporter == nil {
+			out := o.Out
+			if out == nil {
+				out = os.Stdout
+			}
+			o.reporter = NewJSONImportReporter(out)
+		}
+		// lets keep the log output separate from the events so they can be parsed
+		log.SetOutput(os.Stderr)
+		return nil
+	default:
+		return options.InvalidOptionf("output", o.OutputFormat, "supported values are: text, json")
+	}
+}
+
+// progressOut returns where to write the output of commands and pack detection. When reporting JSON events this is
+// stderr so that stdout only contains the events
+func (o *ImportOptions) progressOut() io.Writer {
+	if o.OutputFormat == "json" {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// WaitForPipeline waits for the pipeline of the repository to be setup
+func (o *ImportOptions) WaitForPipeline(repoName string) error {
+	c := &cmdrunner.Command{
+		Name: "jx",
+		Args: []string{"pipeline", "wait", "--owner", o.Organisation, "--repo", repoName},
+		Out:  o.progressOut(),
+		Err:  os.Stderr,
+	}
+	_, err := o.CommandRunner(c)
+	if err != nil {
+		return errors.Wrapf(err, "failed to wait for the pipeline to be setup %s", scm.Join(o.Organisation, repoName))
+	}
+	return nil
+}
+
+// repositoryFullName returns the full name of the repository being imported if it is known
+func (o *ImportOptions) repositoryFullName() string {
+	repoName := o.GitRepositoryOptions.Name
+	if repoName == "" {
+		repoName = o.Repository
+	}
+	if repoName == "" {
+		repoName = o.AppName
+	}
+	if repoName == "" || o.Organisation == "" {
+		return repoName
+	}
+	return scm.Join(o.Organisation, repoName)
+}
+
 // SetReporter overrides the reporter interface
 func (o *ImportOptions) SetReporter(reporter ImportReporter) {
 	o.reporter = reporter
@@ -589,17 +766,28 @@ func (o *ImportOptions) CreateNewRemoteRepository() error {
 	ctx := context.Background()
 	createRepo := o.GitRepositoryOptions
 
-	// need to clear the owner if its a user
-	if o.getCurrentUser() == createRepo.Namespace {
-		createRepo.Namespace = ""
-	}
-	repo, _, err := o.ScmFactory.ScmClient.Repositories.Create(ctx, &createRepo)
-	if err != nil {
-		return errors.Wrapf(err, "failed to create git repository %s/%s", o.GitRepositoryOptions.Namespace, o.GitRepositoryOptions.Name)
+	var repo *scm.Repository
+	if o.Resume {
+		// lets reuse the repository if a previous import created it
+		fullName := scm.Join(o.Organisation, details.Name)
+		repo, _, err = o.ScmFactory.ScmClient.Repositories.Find(ctx, fullName)
+		if err != nil {
+			log.Logger().Debugf("could not find existing repository %s: %s", fullName, err.Error())
+			repo = nil
+		} else if repo != nil {
+			log.Logger().Infof("resuming import with the existing repository %s", info(fullName))
+		}
 	}
-
-	if err != nil {
-		return err
+	if repo == nil {
+		// need to clear the owner if its a user
+		if o.getCurrentUser() == createRepo.Namespace {
+			createRepo.Namespace = ""
+		}
+		repo, _, err = o.ScmFactory.ScmClient.Repositories.Create(ctx, &createRepo)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create git repository %s/%s", o.GitRepositoryOptions.Namespace, o.GitRepositoryOptions.Name)
+		}
+		o.GetReporter().CreatedRemoteRepository(repo.Link)
 	}
 
 	// mostly to default a value in test cases if its missing
@@ -629,6 +817,20 @@ func (o *ImportOptions) CreateNewRemoteRepository() error {
 	if err != nil {
 		return err
 	}
+	if o.state != nil {
+		o.state.CreatedRepository = true
+		o.state.RepositoryURL = repo.Link
+		err = o.saveImportState()
+		if err != nil {
+			return err
+		}
+	}
+	return o.pushNewRepository(repo.Link)
+}
+
+// pushNewRepository pushes to the newly created remote repository
+func (o *ImportOptions) pushNewRepository(repoURL string) error {
+	dir := o.Dir
 
 	// let's use a retry loop to push in case the repository is not yet setup quite yet
 	f := func() error {
@@ -639,11 +841,10 @@ func (o *ImportOptions) CreateNewRemoteRepository() error {
 	bo.InitialInterval = 3 * time.Second
 	bo.MaxElapsedTime = time.Minute
 	bo.Reset()
-	err = backoff.Retry(f, bo)
+	err := backoff.Retry(f, bo)
 	if err != nil {
 		return err
 	}
-	repoURL := repo.Link
 	o.GetReporter().PushedGitRepository(repoURL)
 	return nil
 }
@@ -665,6 +866,20 @@ func (o *ImportOptions) CloneRepository() error {
 		o.RepoURL = repoURL
 	}
 
+	if o.Resume {
+		// lets reuse the clone from the previous import
+		cloneDir := filepath.Join(o.Dir, gitInfo.Name)
+		exists, err := files.FileExists(ImportStateFile(cloneDir))
+		if err != nil {
+			return errors.Wrapf(err, "failed to check for the import state in %s", cloneDir)
+		}
+		if exists {
+			log.Logger().Infof("resuming the import in %s", info(cloneDir))
+			o.Dir = cloneDir
+			return nil
+		}
+	}
+
 	cloneDir, err := files.CreateUniqueDirectory(o.Dir, gitInfo.Name, files.MaximumNewDirectoryAttempts)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create unique directory for '%s'", o.Dir)
@@ -677,6 +892,34 @@ func (o *ImportOptions) CloneRepository() error {
 	return nil
 }
 
+// DiscoverProject loads the project configuration of the local directory and then discovers its git repository
+// so that the configuration is used when initialising git
+func (o *ImportOptions) DiscoverProject() error {
+	dir := o.Dir
+	if !o.DisableDotGitSearch {
+		root, _, err := gitclient.FindGitConfigDir(o.Dir)
+		if err != nil {
+			return err
+		}
+		if root != "" {
+			dir = root
+		}
+	}
+	err := o.loadAndApplyProjectConfig(dir)
+	if err != nil {
+		return err
+	}
+	err = o.DiscoverGit()
+	if err != nil {
+		return err
+	}
+	o.DiscoveredGitURL, err = gitdiscovery.FindGitURLFromDir(o.Dir, true)
+	if err != nil {
+		return errors.Wrapf(err, "failed to discover the git URL")
+	}
+	return nil
+}
+
 // DiscoverGit checks if there is a git clone or prompts the user to import it
 func (o *ImportOptions) DiscoverGit() error {
 	if !o.DisableDotGitSearch {
@@ -783,7 +1026,7 @@ func (o *ImportOptions) doImport() error {
 	}
 
 	if o.DisableStartPipeline {
-		return nil
+		return o.removeImportState()
 	}
 
 	repoName := o.GitRepositoryOptions.Name
@@ -792,48 +1035,46 @@ func (o *ImportOptions) doImport() error {
 	}
 	repoFullName := scm.Join(o.Organisation, repoName)
 
-	if !o.Destination.Jenkins.Enabled && !remoteCluster {
-		c := &cmdrunner.Command{
-			Name: "jx",
-			Args: []string{"pipeline", "wait", "--owner", o.Organisation, "--repo", repoName},
-			Out:  os.Stdout,
-			Err:  os.Stderr,
+	if !o.Destination.Jenkins.Enabled && !remoteCluster && !o.isStepCompleted(StepWaitPipeline) {
+		err = o.WaitForPipeline(repoName)
+		if err != nil {
+			return err
 		}
-		_, err = o.CommandRunner(c)
+		err = o.completeStep(StepWaitPipeline)
 		if err != nil {
-			return errors.Wrapf(err, "failed to wait for the pipeline to be setup %s", repoFullName)
+			return err
 		}
 	}
 
 	// let's git push the build pack changes now to trigger a release
 	//
 	// TODO we could make this an optional Pull request etc?
-	if o.OnCompleteCallback != nil {
+	if o.OnCompleteCallback != nil && !o.isStepCompleted(StepPush) {
 		err = o.OnCompleteCallback()
 		if err != nil {
 			return errors.Wrapf(err, "failed to push git changes")
 		}
+		err = o.completeStep(StepPush)
+		if err != nil {
+			return err
+		}
+	}
+	err = o.removeImportState()
+	if err != nil {
+		return err
 	}
 
 	if o.Destination.Jenkins.Enabled {
 		return nil
 	}
 
-	log.Logger().Info("")
-	log.Logger().Infof("Pipeline should start soon for: %s", info(repoFullName))
-	log.Logger().Info("")
-	log.Logger().Infof("Watch pipeline activity via:    %s", info(fmt.Sprintf("jx get activity -f %s -w", repoFullName)))
-	log.Logger().Infof("Browse the pipeline log via:    %s", info(fmt.Sprintf("jx get build logs %s", repoFullName)))
-	log.Logger().Infof("You can list the pipelines via: %s", info("jx get pipelines"))
-	log.Logger().Infof("When the pipeline is complete:  %s", info("jx get applications"))
-	log.Logger().Info("")
-
+	o.GetReporter().StartedPipeline(repoFullName)
 	return nil
 }
 
-// ReplacePlaceholders replaces app name, git server name, git org, and docker registry org placeholders
+// ReplacePlaceholders replaces app name, git server name, git org, and docker registry org placeholders and renders
+// the go templates configured by a .jx/gotemplate.yaml file
 func (o *ImportOptions) ReplacePlaceholders(gitServerName, dockerRegistryOrg string) error {
-	safeOrganisationName := naming.ToValidName(strings.ToLower(o.Organisation))
 	o.GetReporter().Trace("replacing placeholders in directory %s", o.Dir)
 	o.GetReporter().Trace("app name: %s, git server: %s, org: %s, Docker registry org: %s", o.AppName, gitServerName, o.Organisation, dockerRegistryOrg)
 
@@ -842,23 +1083,29 @@ func (o *ImportOptions) ReplacePlaceholders(gitServerName, dockerRegistryOrg str
 		return err
 	}
 
-	replacer := strings.NewReplacer(
-		constants.PlaceHolderAppName, strings.ToLower(o.AppName),
-		constants.PlaceHolderGitProvider, strings.ToLower(gitServerName),
-		constants.PlaceHolderOrg, safeOrganisationName,
-		constants.PlaceHolderDockerRegistryOrg, strings.ToLower(dockerRegistryOrg))
+	replacer := NewPlaceholderReplacer(o.PlaceholderValues(gitServerName, dockerRegistryOrg))
+	renderer, err := o.newGoTemplateRenderer(gitServerName, dockerRegistryOrg)
+	if err != nil {
+		return err
+	}
 
 	pathsToRename := []string{} // Renaming must be done post-Walk
 	if err := filepath.Walk(o.Dir, func(f string, fi os.FileInfo, _ error) error {
 		if skip, err := o.skipPathForReplacement(f, fi, ignore); skip {
 			return err
 		}
+		if !fi.IsDir() && renderer != nil {
+			var err error
+			f, err = renderer.render(f)
+			if err != nil {
+				return err
+			}
+		}
 		if strings.Contains(filepath.Base(f), constants.PlaceHolderPrefix) {
 			// Prepend so children are renamed before their parents
 			pathsToRename = append([]string{f}, pathsToRename...)
 		}
 		if !fi.IsDir() {
-			// TODO: Apply  https://docs.gomplate.ca/ if .jx/gotemplate.yaml exists
 			if err := replacePlaceholdersInFile(replacer, f); err != nil {
 				return err
 			}
@@ -867,6 +1114,12 @@ func (o *ImportOptions) ReplacePlaceholders(gitServerName, dockerRegistryOrg str
 	}); err != nil {
 		return fmt.Errorf("error replacing placeholders %v", err)
 	}
+	if renderer != nil {
+		err = renderer.remove()
+		if err != nil {
+			return err
+		}
+	}
 
 	for _, path := range pathsToRename {
 		if err := replacePlaceholdersInPathBase(replacer, path); err != nil {
@@ -876,6 +1129,50 @@ func (o *ImportOptions) ReplacePlaceholders(gitServerName, dockerRegistryOrg str
 	return nil
 }
 
+// PlaceholderValues returns the values to use for each placeholder indexed by the placeholder name
+func (o *ImportOptions) PlaceholderValues(gitServerName, dockerRegistryOrg string) map[string]string {
+	organisation := naming.ToValidName(strings.ToLower(o.Organisation))
+	appName := strings.ToLower(o.AppName)
+	values := map[string]string{
+		constants.PlaceHolderAppName:           appName,
+		constants.PlaceHolderGitProvider:       strings.ToLower(gitServerName),
+		constants.PlaceHolderOrg:               organisation,
+		constants.PlaceHolderDockerRegistryOrg: strings.ToLower(dockerRegistryOrg),
+		constants.PlaceHolderRepoName:          o.repositoryName(),
+		constants.PlaceHolderNamespace:         o.Namespace,
+		constants.PlaceHolderDefaultBranch:     o.defaultBranch(),
+		constants.PlaceHolderJavaPackage:       JavaPackageName(organisation, appName),
+	}
+	// lets leave the docker registry placeholder unresolved rather than removing it if it is unknown
+	if o.DockerRegistry != "" {
+		values[constants.PlaceHolderDockerRegistry] = strings.ToLower(o.DockerRegistry)
+	}
+	for k, v := range o.userPlaceholders() {
+		values[k] = v
+	}
+	return values
+}
+
+// NewPlaceholderReplacer creates a replacer for the placeholder values. Longer placeholders are replaced first
+// so that a placeholder which is a prefix of another placeholder does not break it
+func NewPlaceholderReplacer(values map[string]string) *strings.Replacer {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	var oldnew []string
+	for _, k := range keys {
+		oldnew = append(oldnew, k, values[k])
+	}
+	return strings.NewReplacer(oldnew...)
+}
+
 func (o *ImportOptions) skipPathForReplacement(path string, fi os.FileInfo, ignore gitignore.GitIgnore) (bool, error) {
 	relPath, _ := filepath.Rel(o.Dir, path)
 	match := ignore.Relative(relPath, fi.IsDir())
@@ -930,89 +1227,6 @@ func replacePlaceholdersInPathBase(replacer *strings.Replacer, path string) erro
 	return nil
 }
 
-func (o *ImportOptions) addAppNameToGeneratedFile(filename, field, value string) error {
-	dir := filepath.Join(o.Dir, "charts", o.AppName)
-	file := filepath.Join(dir, filename)
-	exists, err := files.FileExists(file)
-	if err != nil {
-		return err
-	}
-	if !exists {
-		// no file so lets ignore this
-		return nil
-	}
-	fileContent, err := os.ReadFile(file)
-	if err != nil {
-		return err
-	}
-
-	lines := strings.Split(string(fileContent), "\n")
-
-	for i, line := range lines {
-		if strings.HasPrefix(line, field) {
-			lines[i] = fmt.Sprintf("%s%s", field, value)
-		}
-	}
-	output := strings.Join(lines, "\n")
-	err = os.WriteFile(file, []byte(output), 0600) // #nosec G703
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-func (o *ImportOptions) renameChartToMatchAppName() error {
-	var oldChartsDir string
-	dir := o.Dir
-	chartsDir := filepath.Join(dir, "charts")
-	exists, err := files.DirExists(chartsDir)
-	if err != nil {
-		return errors.Wrapf(err, "failed to check if the charts directory exists %s", chartsDir)
-	}
-	if !exists {
-		return nil
-	}
-	fileSlice, err := os.ReadDir(chartsDir)
-	if err != nil {
-		return fmt.Errorf("error matching a Jenkins X build pack name with chart folder %v", err)
-	}
-	for _, fi := range fileSlice {
-		if fi.IsDir() {
-			name := fi.Name()
-			// TODO we maybe need to try check if the sub dir named after the build pack matches first?
-			if name != "preview" && name != ".git" {
-				oldChartsDir = filepath.Join(chartsDir, name)
-				break
-			}
-		}
-	}
-	if oldChartsDir != "" {
-		// chart expects folder name to be the same as app name
-		newChartsDir := filepath.Join(dir, "charts", o.AppName)
-
-		exists, err := files.DirExists(oldChartsDir)
-		if err != nil {
-			return err
-		}
-		if exists && oldChartsDir != newChartsDir {
-			err = files.RenameDir(oldChartsDir, newChartsDir, false)
-			if err != nil {
-				return fmt.Errorf("error renaming %s to %s, %v", oldChartsDir, newChartsDir, err)
-			}
-			_, err = os.Stat(newChartsDir)
-			if err != nil {
-				return err
-			}
-		}
-		// now update the chart.yaml
-		err = o.addAppNameToGeneratedFile("Chart.yaml", "name: ", o.AppName)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 func (o *ImportOptions) fixDockerIgnoreFile() error {
 	filename := filepath.Join(o.Dir, ".dockerignore")
 	exists, err := files.FileExists(filename)
@@ -1107,46 +1321,35 @@ func (o *ImportOptions) fixMaven() error {
 	if err != nil {
 		return err
 	}
-	if exists {
-		err = maven.InstallMavenIfRequired(o.CommandRunner)
-		if err != nil {
-			return err
-		}
+	if !exists {
+		return nil
+	}
 
-		// let's ensure the mvn plugins are ok
-		out, err := o.CommandRunner(cmdrunner.NewCommand(dir, "mvn", "io.jenkins.updatebot:updatebot-maven-plugin:"+
-			updateBotMavenPluginVersion+":plugin", "-Dartifact=maven-deploy-plugin", "-Dversion="+constants.MinimumMavenDeployVersion))
-		if err != nil {
-			return fmt.Errorf("failed to update maven deploy plugin: %s output: %s", err, out)
-		}
-		out, err = o.CommandRunner(cmdrunner.NewCommand(dir, "mvn", "io.jenkins.updatebot:updatebot-maven-plugin:"+
-			updateBotMavenPluginVersion+":plugin", "-Dartifact=maven-surefire-plugin", "-Dversion=3.5.4"))
-		if err != nil {
-			return fmt.Errorf("failed to update maven surefire plugin: %s output: %s", err, out)
-		}
+	// let's ensure the mvn plugins are ok
+	modified, err := UpdatePomPluginVersions(pomName, [][2]string{
+		{"maven-deploy-plugin", constants.MinimumMavenDeployVersion},
+		{"maven-surefire-plugin", constants.MinimumMavenSurefireVersion},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update the maven plugins")
+	}
+	if modified {
 		_, err = gitclient.AddAndCommitFiles(o.Git(), dir, "fix(plugins): use a better version of maven plugins")
 		if err != nil {
 			return err
 		}
+	}
 
-		// let's ensure the probe paths are ok
-		out, err = o.CommandRunner(cmdrunner.NewCommand(dir, "mvn", "io.jenkins.updatebot:updatebot-maven-plugin:"+updateBotMavenPluginVersion+":chart"))
-		if err != nil {
-			return fmt.Errorf("failed to update chart: %s output: %s", err, out)
-		}
-		if out != "" {
-			log.Logger().Info(out)
-		}
-		exists, err := files.FileExists(filepath.Join(dir, "charts"))
+	// let's ensure the probe paths are ok
+	modified, err = o.FixProbePath()
+	if err != nil {
+		return errors.Wrapf(err, "failed to fix the probe path of the chart")
+	}
+	if modified {
+		_, err = gitclient.AddAndCommitFiles(o.Git(), dir, "fix(chart): fix up the probe path")
 		if err != nil {
 			return err
 		}
-		if exists {
-			_, err = gitclient.AddAndCommitFiles(o.Git(), dir, "fix(chart): fix up the probe path")
-			if err != nil {
-				return err
-			}
-		}
 	}
 	return nil
 }
@@ -1156,7 +1359,17 @@ func (o *ImportOptions) DefaultsFromTeamSettings() error {
 	if err != nil {
 		return errors.Wrapf(err, "failed to load Team Settings")
 	}
-	return o.DefaultValuesFromTeamSettings(settings)
+	err = o.DefaultValuesFromTeamSettings(settings)
+	if err != nil {
+		return err
+	}
+	if o.DockerRegistry == "" {
+		o.DockerRegistry, err = o.ClusterDockerRegistry()
+		if err != nil {
+			return errors.Wrapf(err, "failed to find the docker registry of the cluster")
+		}
+	}
+	return nil
 }
 
 // DefaultValuesFromTeamSettings defaults the repository options from the given team settings
@@ -1165,16 +1378,14 @@ func (o *ImportOptions) DefaultValuesFromTeamSettings(settings *v1.TeamSettings)
 		o.DeployKind = settings.DeployKind
 	}
 
-	// let's override any deploy o from the team settings if they are not specified
-	/* TODO
+	// let's default any deploy options from the team settings if they are not specified
 	teamDeployOptions := settings.GetDeployOptions()
-	if !o.FlagChanged(OptionCanary) {
+	if !o.FlagChanged(constants.OptionCanary) && (o.projectConfig == nil || o.projectConfig.Canary == nil) {
 		o.DeployOptions.Canary = teamDeployOptions.Canary
 	}
-	if !o.FlagChanged(OptionHPA) {
+	if !o.FlagChanged(constants.OptionHPA) && (o.projectConfig == nil || o.projectConfig.HPA == nil) {
 		o.DeployOptions.HPA = teamDeployOptions.HPA
 	}
-	*/
 	if o.Organisation == "" {
 		o.Organisation = settings.Organisation
 	}
@@ -1187,7 +1398,9 @@ func (o *ImportOptions) DefaultValuesFromTeamSettings(settings *v1.TeamSettings)
 	if o.ScmFactory.GitServerURL == "" {
 		o.ScmFactory.GitServerURL = settings.GitServer
 	}
-	o.GitRepositoryOptions.Private = !settings.GitPublic
+	if o.projectConfig == nil || o.projectConfig.Private == nil {
+		o.GitRepositoryOptions.Private = !settings.GitPublic
+	}
 	o.PipelineServer = settings.GitServer
 	o.PipelineUserName = settings.PipelineUsername
 	return nil
@@ -1281,6 +1494,7 @@ func (o *ImportOptions) waitForSourceRepositoryPullRequest(pullRequestInfo *scm.
 						return nil
 					}
 					log.Logger().Infof("Pull Request %s was merged at sha %s after waiting %s", termcolor.ColorInfo(pr.Link), termcolor.ColorInfo(pr.MergeSha), elaspedString)
+					o.GetReporter().MergedDevRepoPullRequest(pr.Link, pr.MergeSha)
 					return nil
 				} else if pr.Closed {
 					log.Logger().Warnf("Pull Request %s is closed after waiting %s", termcolor.ColorInfo(pr.Link), elaspedString)
@@ -1296,10 +1510,6 @@ func (o *ImportOptions) waitForSourceRepositoryPullRequest(pullRequestInfo *scm.
 	return nil
 }
 
-func (o *ImportOptions) IsGitHubAppMode() (bool, error) {
-	return false, nil
-}
-
 func (o *ImportOptions) defaultGitServerURLFromDevEnv() (string, error) {
 	gitURL := ""
 	if o.DevEnv != nil {
@@ -134,7 +134,7 @@ func assertImport(t *testing.T, testDir string, testcase string, importToJenkins
 	}
 
 	if testcase == mavenCamel || dirName == mavenSpringBoot {
-		o.DisableMaven = testhelpers.TestShouldDisableMaven()
+		o.DisableMaven = false
 	}
 
 	err := o.Run()
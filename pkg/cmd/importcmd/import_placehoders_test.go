@@ -11,9 +11,11 @@ import (
 	"testing"
 
 	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestReplacePlaceholders(t *testing.T) {
@@ -75,3 +77,133 @@ func LoadBytes(dir, name string) ([]byte, error) {
 	}
 	return bytes, nil
 }
+
+func TestReplacePlaceholdersGoTemplates(t *testing.T) {
+	f := t.TempDir()
+
+	testFiles := map[string]string{
+		".jx/gotemplate.yaml":                                  "files:\n- charts/*/values.yaml\nexclude:\n- charts/*/templates/*\nvalues:\n  port: 8080\n",
+		"charts/REPLACE_ME_APP_NAME/values.yaml":               "image: {{ .DockerRegistryOrg }}/{{ .AppName }}\nport: {{ .Values.port }}\nhpa: {{ .HPA }}\n",
+		"charts/REPLACE_ME_APP_NAME/templates/deployment.yaml": "name: {{ .Release.Name }}\n",
+		"config/app.properties.gotmpl":                         "git={{ .GitServer }}/{{ .Organisation | lower }}\ndeploy={{ .DeployKind | default \"default\" }}\n",
+		"README.md":                                            "# REPLACE_ME_APP_NAME {{ .AppName }}\n",
+	}
+	for name, content := range testFiles {
+		path := filepath.Join(f, name)
+		err := os.MkdirAll(filepath.Dir(path), 0700)
+		require.NoError(t, err, "failed to create dir for %s", name)
+		err = os.WriteFile(path, []byte(content), 0600)
+		require.NoError(t, err, "failed to write %s", name)
+	}
+
+	o := importcmd.ImportOptions{}
+	o.Dir = f
+	o.AppName = "bar"
+	o.Organisation = "Foo"
+	o.DeployOptions.HPA = true
+	o.ScmFactory.NoWriteGitCredentialsFile = true
+	o.AddedGoTemplateConfig = true
+
+	err := o.ReplacePlaceholders("github.com", "registry-org")
+	require.NoError(t, err, "failed to replace placeholders")
+
+	expected := map[string]string{
+		"charts/bar/values.yaml":               "image: registry-org/bar\nport: 8080\nhpa: true\n",
+		"charts/bar/templates/deployment.yaml": "name: {{ .Release.Name }}\n",
+		"config/app.properties":                "git=github.com/foo\ndeploy=default\n",
+		"README.md":                            "# bar {{ .AppName }}\n",
+	}
+	for name, content := range expected {
+		data, err := LoadBytes(f, name)
+		require.NoError(t, err, "failed to load %s", name)
+		assert.Equal(t, content, string(data), "content of %s", name)
+	}
+	assert.NoFileExists(t, filepath.Join(f, "config", "app.properties.gotmpl"), "the go template should be removed")
+	assert.NoFileExists(t, filepath.Join(f, ".jx", importcmd.GoTemplateFileName), "the go template configuration should be removed")
+}
+
+func TestReplacePlaceholdersSourceGoTemplateConfig(t *testing.T) {
+	f := t.TempDir()
+	writeTestFiles(t, f, map[string]string{
+		".jx/gotemplate.yaml":      "files:\n- values.yaml\n",
+		"values.yaml":              "name: {{ .AppName }}\n",
+		"docs/example.yaml.gotmpl": "name: {{ .Release.Name }}\n",
+	})
+
+	o := importcmd.ImportOptions{}
+	o.Dir = f
+	o.AppName = "bar"
+	o.Organisation = "foo"
+	o.ScmFactory.NoWriteGitCredentialsFile = true
+
+	err := o.ReplacePlaceholders("github.com", "registry-org")
+	require.NoError(t, err, "failed to replace placeholders")
+
+	assertFileEquals(t, filepath.Join(f, "values.yaml"), "name: bar\n")
+	assertFileEquals(t, filepath.Join(f, "docs", "example.yaml.gotmpl"), "name: {{ .Release.Name }}\n")
+	assert.FileExists(t, filepath.Join(f, ".jx", importcmd.GoTemplateFileName), "the go template configuration of the source should be kept")
+}
+
+func TestPlaceholderValues(t *testing.T) {
+	o := importcmd.ImportOptions{}
+	o.Dir = t.TempDir()
+	o.AppName = "My-App"
+	o.Organisation = "Acme"
+	o.Repository = "my-app-repo"
+	o.Namespace = "jx"
+	o.DockerRegistry = "ghcr.io"
+	o.SetPlaceholders = []string{"SERVICE_PORT=8080", "REPLACE_ME_TEAM=payments"}
+
+	values := o.PlaceholderValues("github.com", "acme")
+	assert.Equal(t, "my-app", values["REPLACE_ME_APP_NAME"], "app name")
+	assert.Equal(t, "my-app-repo", values["REPLACE_ME_REPO_NAME"], "repository name")
+	assert.Equal(t, "jx", values["REPLACE_ME_NAMESPACE"], "namespace")
+	assert.Equal(t, "master", values["REPLACE_ME_DEFAULT_BRANCH"], "default branch")
+	assert.Equal(t, "ghcr.io", values["REPLACE_ME_DOCKER_REGISTRY"], "docker registry")
+	assert.Equal(t, "acme", values["REPLACE_ME_DOCKER_REGISTRY_ORG"], "docker registry org")
+	assert.Equal(t, "acme.my_app", values["REPLACE_ME_JAVA_PACKAGE"], "java package")
+	assert.Equal(t, "8080", values["REPLACE_ME_SERVICE_PORT"], "user defined placeholder")
+	assert.Equal(t, "payments", values["REPLACE_ME_TEAM"], "user defined placeholder with prefix")
+
+	err := os.WriteFile(filepath.Join(o.Dir, "Dockerfile"), []byte("FROM REPLACE_ME_DOCKER_REGISTRY/REPLACE_ME_DOCKER_REGISTRY_ORG/base\nEXPOSE REPLACE_ME_SERVICE_PORT\n"), 0600)
+	require.NoError(t, err, "failed to write Dockerfile")
+
+	err = o.ReplacePlaceholders("github.com", "acme")
+	require.NoError(t, err, "failed to replace placeholders")
+
+	data, err := LoadBytes(o.Dir, "Dockerfile")
+	require.NoError(t, err, "failed to load Dockerfile")
+	assert.Equal(t, "FROM ghcr.io/acme/base\nEXPOSE 8080\n", string(data), "Dockerfile")
+}
+
+func TestJavaPackageName(t *testing.T) {
+	testCases := map[string][]string{
+		"acme.my_app":       {"acme", "my-app"},
+		"com.acme.demo":     {"com.acme", "Demo"},
+		"_123org._new":      {"123org", "new"},
+		"jenkins_x.web_app": {"jenkins-x", "web app!"},
+	}
+	for expected, names := range testCases {
+		assert.Equal(t, expected, importcmd.JavaPackageName(names...), "java package for %v", names)
+	}
+}
+
+func TestDevEnvironmentPlaceholders(t *testing.T) {
+	devEnvCloneDir := t.TempDir()
+	err := os.MkdirAll(filepath.Join(devEnvCloneDir, "extensions"), 0700)
+	require.NoError(t, err, "failed to create extensions dir")
+	err = os.WriteFile(filepath.Join(devEnvCloneDir, "extensions", importcmd.PlaceholdersFileName), []byte("placeholders:\n  TEAM: payments\n  SERVICE_PORT: \"8080\"\n"), 0600)
+	require.NoError(t, err, "failed to write placeholders file")
+
+	o := importcmd.ImportOptions{}
+	o.BatchMode = true
+	o.DevEnv = &v1.Environment{}
+	o.SetPlaceholders = []string{"SERVICE_PORT=9090"}
+
+	_, _, err = o.PickPipelineCatalog(&importcmd.InvokeDraftPack{DevEnvCloneDir: devEnvCloneDir})
+	require.NoError(t, err, "failed to pick the pipeline catalog")
+
+	values := o.PlaceholderValues("github.com", "acme")
+	assert.Equal(t, "payments", values["REPLACE_ME_TEAM"], "placeholder from the dev environment")
+	assert.Equal(t, "9090", values["REPLACE_ME_SERVICE_PORT"], "--set-placeholder should take precedence")
+}
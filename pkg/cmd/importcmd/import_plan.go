@@ -0,0 +1,714 @@
+package importcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/denormal/go-gitignore"
+	"github.com/jenkins-x-plugins/jx-gitops/pkg/apis/gitops/v1alpha1"
+	"github.com/jenkins-x-plugins/jx-project/pkg/constants"
+	jxdraft "github.com/jenkins-x-plugins/jx-project/pkg/draft"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/gitdiscovery"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/options"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// PlanActionAdd the file will be added
+	PlanActionAdd = "add"
+	// PlanActionOverwrite the file exists and will be overwritten
+	PlanActionOverwrite = "overwrite"
+	// PlanActionModify the file exists and will be modified
+	PlanActionModify = "modify"
+	// PlanActionMove the file will be moved from the path in From
+	PlanActionMove = "move"
+)
+
+// ImportPlan describes what an import would do without changing anything
+type ImportPlan struct {
+	// Dir the directory being imported
+	Dir string `json:"dir"`
+	// AppName the name of the application
+	AppName string `json:"appName"`
+	// Languages the languages detected in the source code
+	Languages []PlanLanguage `json:"languages,omitempty"`
+	// Pack the pipeline catalog pack which would be used
+	Pack string `json:"pack,omitempty"`
+	// Catalog the pipeline catalog the pack comes from
+	Catalog *PlanCatalog `json:"catalog,omitempty"`
+	// Placeholders the values used for each placeholder
+	Placeholders map[string]string `json:"placeholders,omitempty"`
+	// Files the files which would be added, changed or moved. The maven plugin versions and chart probe path fixes
+	// made after the pack is applied are not included
+	Files []PlanFile `json:"files,omitempty"`
+	// Repository the git repository which would be used or created
+	Repository *PlanRepository `json:"repository,omitempty"`
+	// SourceConfig the entry the Pull Request on the dev repository would add
+	SourceConfig *PlanSourceConfig `json:"sourceConfig,omitempty"`
+}
+
+// PlanLanguage a language detected in the source code
+type PlanLanguage struct {
+	Language string  `json:"language"`
+	Percent  float64 `json:"percent"`
+}
+
+// PlanCatalog the pipeline catalog used for the pack
+type PlanCatalog struct {
+	GitURL string `json:"gitUrl,omitempty"`
+	GitRef string `json:"gitRef,omitempty"`
+	Commit string `json:"commit,omitempty"`
+	Dir    string `json:"dir,omitempty"`
+}
+
+// PlanFile a file which would be added or changed
+type PlanFile struct {
+	// Path the path relative to the imported directory after any placeholders in the name are replaced
+	Path string `json:"path"`
+	// Action one of add, overwrite, modify or move
+	Action string `json:"action"`
+	// From the path relative to the imported directory the file is moved from
+	From string `json:"from,omitempty"`
+	// Placeholders the placeholders replaced in this file
+	Placeholders map[string]string `json:"placeholders,omitempty"`
+}
+
+// PlanRepository the git repository for the import
+type PlanRepository struct {
+	Create    bool   `json:"create"`
+	GitServer string `json:"gitServer,omitempty"`
+	Owner     string `json:"owner,omitempty"`
+	Name      string `json:"name,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Private   bool   `json:"private,omitempty"`
+}
+
+// PlanSourceConfig the source config entry the dev repository Pull Request would add
+type PlanSourceConfig struct {
+	DevGitURL    string              `json:"devGitUrl,omitempty"`
+	File         string              `json:"file"`
+	Provider     string              `json:"provider,omitempty"`
+	ProviderKind string              `json:"providerKind,omitempty"`
+	Owner        string              `json:"owner,omitempty"`
+	Jenkins      string              `json:"jenkins,omitempty"`
+	Repository   v1alpha1.Repository `json:"repository"`
+}
+
+// RunPlan creates the import plan and writes it to the output
+func (o *ImportOptions) RunPlan() error {
+	out := o.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	// lets keep the log output separate from the plan so it can be parsed
+	log.SetOutput(os.Stderr)
+
+	plan, err := o.CreatePlan()
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the import plan")
+	}
+	return WritePlan(out, plan, o.PlanFormat)
+}
+
+// WritePlan writes the plan in the given format which is either json or yaml
+func WritePlan(out io.Writer, plan *ImportPlan, format string) error {
+	var data []byte
+	var err error
+	switch strings.ToLower(format) {
+	case "json":
+		data, err = json.MarshalIndent(plan, "", "  ")
+		data = append(data, '\n')
+	case "yaml", "yml":
+		data, err = yaml.Marshal(plan)
+	default:
+		return errors.Errorf("unsupported plan format '%s'. Should be one of json, yaml", format)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal the import plan")
+	}
+	_, err = out.Write(data)
+	return err
+}
+
+// CreatePlan works out what the import would do without changing the source directory or any git repositories
+func (o *ImportOptions) CreatePlan() (*ImportPlan, error) {
+	var err error
+	o.DiscoveredGitURL = o.RepoURL
+	if o.RepoURL != "" {
+		cloneURL, err := o.ScmFactory.CreateAuthenticatedURL(o.RepoURL)
+		if err != nil {
+			return nil, err
+		}
+		tmpDir, err := os.MkdirTemp("", "jx-import-plan-")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create temporary directory")
+		}
+		defer os.RemoveAll(tmpDir) //nolint:errcheck
+		o.Dir, err = gitclient.CloneToDir(o.Git(), cloneURL, tmpDir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to clone %s", o.RepoURL)
+		}
+	} else {
+		root, _, err := gitclient.FindGitConfigDir(o.Dir)
+		if err != nil {
+			return nil, err
+		}
+		if root != "" {
+			o.Dir = root
+			o.DiscoveredGitURL, err = gitdiscovery.FindGitURLFromDir(o.Dir, true)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to discover the git URL")
+			}
+		}
+	}
+	if o.DiscoveredGitURL != "" {
+		o.gitInfo, err = giturl.ParseGitURL(o.DiscoveredGitURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = o.loadAndApplyProjectConfig(o.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if o.Monorepo {
+		return nil, options.InvalidOptionf("plan", o.PlanFormat, "cannot be used with --monorepo")
+	}
+	err = o.DefaultsFromTeamSettings()
+	if err != nil {
+		return nil, err
+	}
+	err = o.defaultAppName()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ImportPlan{
+		Dir:        o.Dir,
+		AppName:    o.AppName,
+		Repository: o.planRepository(),
+	}
+
+	if !o.DisableBuildPack {
+		g := filepath.Join(o.Dir, ".lighthouse", "*", "triggers.yaml")
+		matches, err := filepath.Glob(g)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to evaluate glob %s", g)
+		}
+		if len(matches) > 0 {
+			o.DisableBuildPack = true
+		}
+	}
+
+	devEnvCloneDir, err := o.CloneDevEnvironment()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to clone dev env git repository")
+	}
+	defer os.RemoveAll(devEnvCloneDir) //nolint:errcheck
+
+	if !o.DisableBuildPack {
+		err = o.planBuildPack(plan, devEnvCloneDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !o.NoDevPullRequest {
+		plan.SourceConfig = o.planSourceConfig(plan.Repository)
+	}
+	return plan, nil
+}
+
+func (o *ImportOptions) planRepository() *PlanRepository {
+	if o.gitInfo != nil {
+		return &PlanRepository{
+			GitServer: o.gitInfo.HostURL(),
+			Owner:     o.gitInfo.Organisation,
+			Name:      o.gitInfo.Name,
+			URL:       o.gitInfo.URLWithoutUser(),
+		}
+	}
+	owner := o.getOrganisationOrCurrentUser()
+	name := o.GitRepositoryOptions.Name
+	if name == "" {
+		name = o.Repository
+	}
+	if name == "" {
+		name = o.AppName
+	}
+	gitServer := o.ScmFactory.GitServerURL
+	return &PlanRepository{
+		Create:    true,
+		GitServer: gitServer,
+		Owner:     owner,
+		Name:      name,
+		URL:       stringhelpers.UrlJoin(gitServer, owner, name),
+		Private:   o.GitRepositoryOptions.Private,
+	}
+}
+
+func (o *ImportOptions) planSourceConfig(repo *PlanRepository) *PlanSourceConfig {
+	devGitURL := ""
+	if o.DevEnv != nil {
+		devGitURL = o.DevEnv.Spec.Source.URL
+	}
+	scheduler := o.SchedulerName
+	if scheduler == "" && !o.DisableBuildPack {
+		scheduler = "in-repo"
+	}
+	return &PlanSourceConfig{
+		DevGitURL:    devGitURL,
+		File:         filepath.Join(".jx", "gitops", v1alpha1.SourceConfigFileName),
+		Provider:     repo.GitServer,
+		ProviderKind: o.ScmFactory.GitKind,
+		Owner:        repo.Owner,
+		Jenkins:      o.Destination.Jenkins.Server,
+		Repository: v1alpha1.Repository{
+			Name:      repo.Name,
+			Scheduler: scheduler,
+		},
+	}
+}
+
+func (o *ImportOptions) planBuildPack(plan *ImportPlan, devEnvCloneDir string) error {
+	i := &InvokeDraftPack{
+		Dir:             o.Dir,
+		DevEnvCloneDir:  devEnvCloneDir,
+		CustomDraftPack: o.Pack,
+		InitialisedGit:  o.InitialisedGit,
+		DisableAddFiles: true,
+		Out:             os.Stderr,
+	}
+	pack, err := o.InvokeDraftPack(i)
+	if err != nil {
+		return err
+	}
+	plan.Pack = pack
+
+	plan.Catalog = &PlanCatalog{
+		Dir: i.PacksDir,
+	}
+	if i.Catalog != nil {
+		plan.Catalog.GitURL = i.Catalog.GitURL
+		plan.Catalog.GitRef = i.Catalog.GitRef
+	}
+	plan.Catalog.Commit, err = gitclient.GetLatestCommitSha(o.Git(), i.PacksDir)
+	if err != nil {
+		log.Logger().Debugf("failed to find the commit of the pipeline catalog in %s: %s", i.PacksDir, err.Error())
+	}
+
+	langs, err := jxdraft.DetectLanguages(o.Dir)
+	if err != nil {
+		log.Logger().Debugf("failed to detect languages in %s: %s", o.Dir, err.Error())
+	}
+	for _, l := range langs {
+		plan.Languages = append(plan.Languages, PlanLanguage{Language: l.Language, Percent: l.Percent})
+	}
+
+	gitServerName, err := o.getGitServerName()
+	if err != nil {
+		return err
+	}
+	values := o.PlaceholderValues(gitServerName, o.getDockerRegistryOrg())
+	plan.Placeholders = values
+
+	planFiles, err := o.planPackFiles(filepath.Join(i.PacksDir, pack), values)
+	if err != nil {
+		return err
+	}
+	existingFiles, err := o.planExistingFiles(values)
+	if err != nil {
+		return err
+	}
+	moves, err := o.planChartMove()
+	if err != nil {
+		return err
+	}
+	m := map[string]PlanFile{}
+	for _, f := range existingFiles {
+		if to, ok := moves[f.Path]; ok {
+			f.From = f.Path
+			f.Path = to
+			f.Action = PlanActionMove
+		}
+		m[f.Path] = f
+	}
+	for from, to := range moves {
+		if _, ok := m[to]; !ok {
+			m[to] = PlanFile{Path: to, Action: PlanActionMove, From: from}
+		}
+	}
+	// files from the pack take precedence
+	for _, f := range planFiles {
+		m[f.Path] = f
+	}
+
+	err = o.planKptfiles(m, filepath.Join(i.PacksDir, pack))
+	if err != nil {
+		return err
+	}
+	err = o.planDockerfile(m, pack)
+	if err != nil {
+		return err
+	}
+	err = o.planDeployOptions(m)
+	if err != nil {
+		return err
+	}
+	err = o.planOwnersFiles(m)
+	if err != nil {
+		return err
+	}
+	for _, f := range m {
+		plan.Files = append(plan.Files, f)
+	}
+	sort.Slice(plan.Files, func(i, j int) bool {
+		return plan.Files[i].Path < plan.Files[j].Path
+	})
+	return nil
+}
+
+// planPackFiles returns the files Pack.SaveDir would add or overwrite
+func (o *ImportOptions) planPackFiles(packDir string, values map[string]string) ([]PlanFile, error) {
+	p, err := FromDir(packDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not load %s", packDir)
+	}
+	defer p.Close()
+
+	replacer := NewPlaceholderReplacer(values)
+	dest := o.Dir
+	chartsDir := filepath.Join(dest, ChartsDir)
+	for _, path := range []string{filepath.Join(chartsDir, o.AppName), filepath.Join(chartsDir, chartutil.ChartfileName)} {
+		exists, err := files.FileExists(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to check if file exists %s", path)
+		}
+		existsDir, err := files.DirExists(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to check if dir exists %s", path)
+		}
+		if exists || existsDir {
+			p.Charts = nil
+		}
+	}
+
+	if o.PackFilter != nil {
+		o.PackFilter(p)
+	}
+
+	var answer []PlanFile
+	addFile := func(relPath string, data []byte) error {
+		relPath = replacer.Replace(relPath)
+		action := PlanActionAdd
+		exists, err := files.FileExists(filepath.Join(dest, relPath))
+		if err != nil {
+			return errors.Wrapf(err, "failed to check if file exists %s", relPath)
+		}
+		if exists {
+			action = PlanActionOverwrite
+		}
+		answer = append(answer, PlanFile{
+			Path:         relPath,
+			Action:       action,
+			Placeholders: findPlaceholders(string(data), values),
+		})
+		return nil
+	}
+
+	for _, c := range p.Charts {
+		// the chart is renamed to the app name after it is saved
+		chartName := o.AppName
+		if c.Metadata.Name == "preview" {
+			chartName = c.Metadata.Name
+		}
+		chartDir := filepath.Join(ChartsDir, chartName)
+		raw := map[string][]byte{}
+		for _, f := range c.Raw {
+			raw[f.Name] = f.Data
+		}
+		err = addFile(filepath.Join(chartDir, chartutil.ChartfileName), raw[chartutil.ChartfileName])
+		if err != nil {
+			return nil, err
+		}
+		if len(c.Values) > 0 {
+			err = addFile(filepath.Join(chartDir, chartutil.ValuesfileName), raw[chartutil.ValuesfileName])
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, f := range c.Templates {
+			err = addFile(filepath.Join(chartDir, f.Name), f.Data)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, f := range c.Files {
+			err = addFile(filepath.Join(chartDir, f.Name), f.Data)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, dep := range c.Dependencies() {
+			name := fmt.Sprintf("%s-%s.tgz", dep.Name(), dep.Metadata.Version)
+			err = addFile(filepath.Join(chartDir, ChartsDir, name), nil)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for relPath, f := range p.Files {
+		exists, err := files.FileExists(filepath.Join(dest, relPath))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to check if path exists %s", relPath)
+		}
+		if exists {
+			// SaveDir does not overwrite existing files
+			continue
+		}
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read pack file %s", relPath)
+		}
+		err = addFile(relPath, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return answer, nil
+}
+
+// planExistingFiles returns the existing files ReplacePlaceholders would modify
+func (o *ImportOptions) planExistingFiles(values map[string]string) ([]PlanFile, error) {
+	ignore, err := gitignore.NewRepository(o.Dir)
+	if err != nil {
+		return nil, err
+	}
+	replacer := NewPlaceholderReplacer(values)
+
+	var answer []PlanFile
+	err = filepath.Walk(o.Dir, func(f string, fi os.FileInfo, _ error) error {
+		if skip, err := o.skipPathForReplacement(f, fi, ignore); skip {
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read file %s", f)
+		}
+		relPath, err := filepath.Rel(o.Dir, f)
+		if err != nil {
+			return err
+		}
+		placeholders := findPlaceholders(string(data), values)
+		newPath := replacer.Replace(relPath)
+		if len(placeholders) == 0 && newPath == relPath {
+			return nil
+		}
+		answer = append(answer, PlanFile{
+			Path:         newPath,
+			Action:       PlanActionModify,
+			Placeholders: placeholders,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find placeholders in %s", o.Dir)
+	}
+	return answer, nil
+}
+
+// planChartMove returns the new paths of the files of a chart in the charts folder which are moved into
+// charts/<app> indexed by their current path
+func (o *ImportOptions) planChartMove() (map[string]string, error) {
+	chartsDir := filepath.Join(o.Dir, ChartsDir)
+	appDir := filepath.Join(chartsDir, o.AppName)
+	exists, err := files.DirExists(appDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check if dir exists %s", appDir)
+	}
+	if exists {
+		return nil, nil
+	}
+	chartFile := filepath.Join(chartsDir, chartutil.ChartfileName)
+	exists, err = files.FileExists(chartFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check if file exists %s", chartFile)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	answer := map[string]string{}
+	err = filepath.Walk(chartsDir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(chartsDir, path)
+		if err != nil {
+			return err
+		}
+		answer[filepath.Join(ChartsDir, rel)] = filepath.Join(ChartsDir, o.AppName, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find the chart files in %s", chartsDir)
+	}
+	return answer, nil
+}
+
+// planKptfiles adds the v1alpha1 Kptfiles which would be migrated to kpt v1 and the Kptfiles which would be created
+// for the pipelines of the pack
+func (o *ImportOptions) planKptfiles(m map[string]PlanFile, packDir string) error {
+	paths, err := FindLighthouseKptfiles(o.Dir)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		kf, err := LoadKptfile(path)
+		if err != nil {
+			return err
+		}
+		if kf.APIVersion != KptAPIVersionV1Alpha1 {
+			continue
+		}
+		rel, err := filepath.Rel(o.Dir, path)
+		if err != nil {
+			return err
+		}
+		if _, ok := m[rel]; !ok {
+			m[rel] = PlanFile{Path: rel, Action: PlanActionModify}
+		}
+	}
+
+	lighthouseDir := filepath.Join(packDir, ".lighthouse")
+	exists, err := files.DirExists(lighthouseDir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if dir exists %s", lighthouseDir)
+	}
+	if !exists {
+		return nil
+	}
+	fileSlice, err := os.ReadDir(lighthouseDir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read dir %s", lighthouseDir)
+	}
+	for _, f := range fileSlice {
+		if !f.IsDir() {
+			continue
+		}
+		missing, err := o.isMissingKptfile(lighthouseDir, f.Name())
+		if err != nil {
+			return err
+		}
+		if missing {
+			rel := filepath.Join(".lighthouse", f.Name(), KptfileName)
+			m[rel] = PlanFile{Path: rel, Action: PlanActionAdd}
+		}
+	}
+	return nil
+}
+
+// planDockerfile adds the Dockerfile and .dockerignore which would be generated if neither the source nor the pack
+// has a Dockerfile
+func (o *ImportOptions) planDockerfile(m map[string]PlanFile, pack string) error {
+	if o.DisableDockerfile {
+		return nil
+	}
+	if _, ok := m["Dockerfile"]; ok {
+		return nil
+	}
+	path := filepath.Join(o.Dir, "Dockerfile")
+	exists, err := files.FileExists(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if file exists %s", path)
+	}
+	if exists {
+		return nil
+	}
+	d, err := NewDockerfile(o.Dir, pack, o.dockerfileSettings)
+	if err != nil {
+		return errors.Wrapf(err, "failed to detect the Dockerfile language")
+	}
+	if d == nil {
+		return nil
+	}
+	m["Dockerfile"] = PlanFile{Path: "Dockerfile", Action: PlanActionAdd}
+
+	path = filepath.Join(o.Dir, ".dockerignore")
+	exists, err = files.FileExists(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if file exists %s", path)
+	}
+	if !exists {
+		m[".dockerignore"] = PlanFile{Path: ".dockerignore", Action: PlanActionAdd}
+	}
+	return nil
+}
+
+// planDeployOptions adds the values.yaml of an existing chart if the deploy options would modify it
+func (o *ImportOptions) planDeployOptions(m map[string]PlanFile) error {
+	rel := filepath.Join(ChartsDir, o.AppName, chartutil.ValuesfileName)
+	if _, ok := m[rel]; ok {
+		return nil
+	}
+	node, err := loadYAMLFile(filepath.Join(o.Dir, rel))
+	if err != nil || node == nil {
+		return err
+	}
+	if o.applyDeployOptions(node.YNode()) {
+		m[rel] = PlanFile{Path: rel, Action: PlanActionModify}
+	}
+	return nil
+}
+
+// planOwnersFiles adds the OWNERS and OWNERS_ALIASES files which would be created
+func (o *ImportOptions) planOwnersFiles(m map[string]PlanFile) error {
+	for _, name := range []string{"OWNERS", "OWNERS_ALIASES"} {
+		if _, ok := m[name]; ok {
+			continue
+		}
+		path := filepath.Join(o.Dir, name)
+		exists, err := files.FileExists(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to check if file exists %s", path)
+		}
+		if !exists {
+			m[name] = PlanFile{Path: name, Action: PlanActionAdd}
+		}
+	}
+	return nil
+}
+
+// findPlaceholders returns the placeholders and their values found in the given text
+func findPlaceholders(text string, values map[string]string) map[string]string {
+	if !strings.Contains(text, constants.PlaceHolderPrefix) {
+		return nil
+	}
+	var answer map[string]string
+	for k, v := range values {
+		if strings.Contains(text, k) {
+			if answer == nil {
+				answer = map[string]string{}
+			}
+			answer[k] = v
+		}
+	}
+	return answer
+}
@@ -0,0 +1,224 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/testimports"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportPlan(t *testing.T) {
+	srcDir := t.TempDir()
+	err := files.CopyDirOverwrite(filepath.Join("test_data", "import_projects", "golang"), srcDir)
+	require.NoError(t, err, "failed to copy source")
+
+	devEnvDir := t.TempDir()
+	out, err := exec.Command("git", "init", devEnvDir).CombinedOutput()
+	require.NoError(t, err, "failed to create dev env git repository: %s", string(out))
+
+	o := &importcmd.ImportOptions{}
+	_, devEnv, _ := testimports.SetFakeClients(t, o, false)
+	devEnv.Spec.Source.URL = devEnvDir
+	o.DevEnv = devEnv
+	o.Dir = srcDir
+	o.AppName = "myapp"
+	o.Organisation = "myorg"
+	o.Pack = "go"
+	o.BatchMode = true
+	o.ScmFactory.GitServerURL = "https://github.com"
+	o.PipelineCatalogDir = filepath.Join("test_data", "plan", "packs")
+	o.PlanFormat = "json"
+	buf := &bytes.Buffer{}
+	o.Out = buf
+
+	err = o.Run()
+	require.NoError(t, err, "failed to create the plan")
+
+	plan := &importcmd.ImportPlan{}
+	err = json.Unmarshal(buf.Bytes(), plan)
+	require.NoError(t, err, "failed to parse plan %s", buf.String())
+
+	assert.Equal(t, "myapp", plan.AppName, "plan.AppName")
+	assert.Equal(t, "go", plan.Pack, "plan.Pack")
+
+	m := map[string]importcmd.PlanFile{}
+	for _, f := range plan.Files {
+		m[f.Path] = f
+	}
+	for _, path := range []string{"Dockerfile", filepath.Join("charts", "myapp", "Chart.yaml"), filepath.Join("charts", "myapp", "values.yaml"), filepath.Join(".lighthouse", "jenkins-x", "triggers.yaml"),
+		filepath.Join(".lighthouse", "jenkins-x", "Kptfile"), "OWNERS", "OWNERS_ALIASES"} {
+		f, ok := m[path]
+		if assert.True(t, ok, "should have planned file %s", path) {
+			assert.Equal(t, importcmd.PlanActionAdd, f.Action, "action for %s", path)
+		}
+	}
+	assert.Equal(t, "myapp", m["Dockerfile"].Placeholders["REPLACE_ME_APP_NAME"], "Dockerfile placeholder")
+
+	require.NotNil(t, plan.Repository, "plan.Repository")
+	assert.True(t, plan.Repository.Create, "plan.Repository.Create")
+	assert.Equal(t, "myorg", plan.Repository.Owner, "plan.Repository.Owner")
+	assert.Equal(t, "https://github.com/myorg/myapp", plan.Repository.URL, "plan.Repository.URL")
+
+	require.NotNil(t, plan.SourceConfig, "plan.SourceConfig")
+	assert.Equal(t, "myapp", plan.SourceConfig.Repository.Name, "plan.SourceConfig.Repository.Name")
+
+	for _, path := range []string{"Dockerfile", ".git", "charts"} {
+		assert.NoFileExists(t, filepath.Join(srcDir, path), "plan should not have created %s", path)
+		assert.NoDirExists(t, filepath.Join(srcDir, path), "plan should not have created %s", path)
+	}
+}
+
+func TestImportPlanExistingChart(t *testing.T) {
+	packsDir := t.TempDir()
+	err := files.CopyDirOverwrite(filepath.Join("test_data", "plan", "packs"), packsDir)
+	require.NoError(t, err, "failed to copy packs")
+	require.NoError(t, os.Remove(filepath.Join(packsDir, "go", "Dockerfile")), "failed to remove the pack Dockerfile")
+
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		expected []importcmd.PlanFile
+	}{
+		{
+			name: "move",
+			files: map[string]string{
+				"charts/Chart.yaml":  "apiVersion: v2\nname: old\nversion: 0.1.0\n",
+				"charts/values.yaml": "replicaCount: 1\n",
+			},
+			expected: []importcmd.PlanFile{
+				{Path: ".dockerignore", Action: importcmd.PlanActionAdd},
+				{Path: "Dockerfile", Action: importcmd.PlanActionAdd},
+				{Path: filepath.Join("charts", "myapp", "Chart.yaml"), Action: importcmd.PlanActionMove, From: filepath.Join("charts", "Chart.yaml")},
+				{Path: filepath.Join("charts", "myapp", "values.yaml"), Action: importcmd.PlanActionMove, From: filepath.Join("charts", "values.yaml")},
+			},
+		},
+		{
+			name: "deploy-options",
+			files: map[string]string{
+				"charts/myapp/Chart.yaml":  "apiVersion: v2\nname: myapp\nversion: 0.1.0\n",
+				"charts/myapp/values.yaml": "replicaCount: 1\n",
+			},
+			expected: []importcmd.PlanFile{
+				{Path: ".dockerignore", Action: importcmd.PlanActionAdd},
+				{Path: "Dockerfile", Action: importcmd.PlanActionAdd},
+				{Path: filepath.Join("charts", "myapp", "values.yaml"), Action: importcmd.PlanActionModify},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srcDir := t.TempDir()
+			tc.files["go.mod"] = "module example.com/myapp\n\ngo 1.21\n"
+			tc.files["main.go"] = "package main\n\nfunc main() {}\n"
+			writeTestFiles(t, srcDir, tc.files)
+
+			devEnvDir := t.TempDir()
+			out, err := exec.Command("git", "init", devEnvDir).CombinedOutput()
+			require.NoError(t, err, "failed to create dev env git repository: %s", string(out))
+
+			o := &importcmd.ImportOptions{}
+			_, devEnv, _ := testimports.SetFakeClients(t, o, false)
+			devEnv.Spec.Source.URL = devEnvDir
+			o.DevEnv = devEnv
+			o.Dir = srcDir
+			o.AppName = "myapp"
+			o.Organisation = "myorg"
+			o.Pack = "go"
+			o.DeployKind = "knative"
+			o.BatchMode = true
+			o.ScmFactory.GitServerURL = "https://github.com"
+			o.PipelineCatalogDir = packsDir
+
+			plan, err := o.CreatePlan()
+			require.NoError(t, err, "failed to create the plan")
+
+			m := map[string]importcmd.PlanFile{}
+			for _, f := range plan.Files {
+				m[f.Path] = f
+			}
+			for _, e := range tc.expected {
+				f, ok := m[e.Path]
+				if assert.True(t, ok, "should have planned file %s", e.Path) {
+					assert.Equal(t, e.Action, f.Action, "action for %s", e.Path)
+					assert.Equal(t, e.From, f.From, "from for %s", e.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestImportPlanGoLibrary(t *testing.T) {
+	packsDir := newTestGoLibraryPacks(t)
+
+	srcDir := t.TempDir()
+	writeTestFiles(t, srcDir, map[string]string{
+		"go.mod": "module example.com/mylib\n\ngo 1.21\n",
+		"lib.go": "package mylib\n\n// Hello returns a greeting\nfunc Hello() string {\n\treturn \"hello\"\n}\n",
+	})
+
+	devEnvDir := t.TempDir()
+	out, err := exec.Command("git", "init", devEnvDir).CombinedOutput()
+	require.NoError(t, err, "failed to create dev env git repository: %s", string(out))
+
+	o := &importcmd.ImportOptions{}
+	_, devEnv, _ := testimports.SetFakeClients(t, o, false)
+	devEnv.Spec.Source.URL = devEnvDir
+	o.DevEnv = devEnv
+	o.Dir = srcDir
+	o.AppName = "mylib"
+	o.Organisation = "myorg"
+	// the fake input confirms switching the explicit pack to the release only pack
+	o.Pack = "go"
+	o.ScmFactory.GitServerURL = "https://github.com"
+	o.PipelineCatalogDir = packsDir
+
+	plan, err := o.CreatePlan()
+	require.NoError(t, err, "failed to create the plan")
+
+	assert.Equal(t, importcmd.GOLIBRARY, plan.Pack, "plan.Pack")
+	for _, f := range plan.Files {
+		assert.NotEqual(t, "Dockerfile", f.Path, "should not plan a Dockerfile for a library")
+		assert.NotContains(t, f.Path, importcmd.ChartsDir, "should not plan a chart for a library")
+	}
+}
+
+// newTestGoLibraryPacks returns a copy of the test packs with a release only go-library pack
+func newTestGoLibraryPacks(t *testing.T) string {
+	packsDir := t.TempDir()
+	err := files.CopyDirOverwrite(filepath.Join("test_data", "plan", "packs"), packsDir)
+	require.NoError(t, err, "failed to copy packs")
+	writeTestFiles(t, filepath.Join(packsDir, importcmd.GOLIBRARY), map[string]string{
+		".lighthouse/jenkins-x/triggers.yaml": "apiVersion: config.lighthouse.jenkins-x.io/v1alpha1\nkind: TriggerConfig\nspec:\n  postsubmits:\n  - name: release\n    context: release\n    source: release.yaml\n    branches:\n    - ^main$\n    - ^master$\n",
+		".lighthouse/jenkins-x/release.yaml":  "apiVersion: tekton.dev/v1beta1\nkind: PipelineRun\nmetadata:\n  name: release\nspec:\n  pipelineSpec:\n    tasks:\n    - name: from-build-pack\n      taskSpec:\n        steps:\n        - name: release\n          image: golang:1.21\n          script: |\n            #!/bin/sh\n            make release\n",
+	})
+	return packsDir
+}
+
+func TestImportPlanMonorepo(t *testing.T) {
+	o := &importcmd.ImportOptions{}
+	testimports.SetFakeClients(t, o, false)
+	o.Dir = t.TempDir()
+	o.Monorepo = true
+	o.PlanFormat = "json"
+
+	_, err := o.CreatePlan()
+	require.Error(t, err, "should not plan a monorepo import")
+	assert.Contains(t, err.Error(), "--monorepo")
+}
+
+func TestWritePlanUnsupportedFormat(t *testing.T) {
+	err := importcmd.WritePlan(&bytes.Buffer{}, &importcmd.ImportPlan{}, "xml")
+	assert.Error(t, err, "should fail for xml format")
+}
@@ -0,0 +1,205 @@
+package importcmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/options"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// ImportProjectResult the result of importing a single repository from a git organisation
+type ImportProjectResult struct {
+	Name  string
+	URL   string
+	Error error
+}
+
+// ImportProjectsFromGitHub imports the selected repositories from the git organisation
+func (o *ImportOptions) ImportProjectsFromGitHub() error {
+	repos, err := o.PickRepositories()
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		log.Logger().Infof("no repositories selected to import")
+		return nil
+	}
+
+	log.Logger().Info("Selected repositories")
+	var results []*ImportProjectResult
+	for _, r := range repos {
+		log.Logger().Infof("Importing repository %s", termcolor.ColorInfo(r.Name))
+
+		result := &ImportProjectResult{
+			Name: r.Name,
+			URL:  r.Clone,
+		}
+		o2 := o.ImportOptionsForRepository(r)
+		result.Error = o2.Run()
+		if result.Error != nil {
+			log.Logger().Errorf("failed to import repository %s: %s", r.Name, result.Error.Error())
+		}
+		results = append(results, result)
+	}
+	return o.reportImportProjectResults(results)
+}
+
+// PickRepositories lists the repositories in the organisation and lets the user pick which ones to import
+func (o *ImportOptions) PickRepositories() ([]*scm.Repository, error) {
+	owner := o.Organisation
+	if owner == "" {
+		if o.BatchMode {
+			return nil, options.MissingOption("org")
+		}
+		var err error
+		owner, err = o.PickOwner("")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to pick owner")
+		}
+		o.Organisation = owner
+	}
+
+	repos, err := o.listRepositories(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	m := map[string]*scm.Repository{}
+	var names []string
+	for _, r := range repos {
+		if r.Archived {
+			continue
+		}
+		if o.SelectFilter != "" && !strings.Contains(r.Name, o.SelectFilter) {
+			continue
+		}
+		m[r.Name] = r
+		names = append(names, r.Name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return nil, errors.Errorf("no repositories found in %s matching filter '%s'", owner, o.SelectFilter)
+	}
+
+	if o.BatchMode {
+		if !o.SelectAll {
+			return nil, errors.Errorf("please specify --all to import repositories from %s in batch mode", owner)
+		}
+	} else {
+		names, err = o.Input.SelectNames(names, "Which repositories do you want to import", o.SelectAll,
+			"pick the repositories you wish to import into Jenkins X")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to select the repositories")
+		}
+	}
+
+	var answer []*scm.Repository
+	for _, name := range names {
+		r := m[name]
+		if r != nil {
+			answer = append(answer, r)
+		}
+	}
+	return answer, nil
+}
+
+// listRepositories lists all the repositories for the given owner handling paging
+func (o *ImportOptions) listRepositories(owner string) ([]*scm.Repository, error) {
+	scmClient := o.ScmFactory.ScmClient
+	if scmClient == nil {
+		return nil, errors.Errorf("no SCM client")
+	}
+	ctx := context.Background()
+	isUser := owner == o.getCurrentUser()
+
+	var answer []*scm.Repository
+	opts := &scm.ListOptions{
+		Page: 1,
+		Size: 100,
+	}
+	for {
+		var repos []*scm.Repository
+		var err error
+		if isUser {
+			repos, _, err = scmClient.Repositories.List(ctx, opts)
+		} else {
+			repos, _, err = scmClient.Repositories.ListOrganisation(ctx, owner, opts)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list repositories for %s", owner)
+		}
+		for _, r := range repos {
+			// listing the current users repositories may include other owners
+			if isUser && r.Namespace != "" && r.Namespace != owner {
+				continue
+			}
+			answer = append(answer, r)
+		}
+		if len(repos) < opts.Size {
+			break
+		}
+		opts.Page++
+	}
+	return answer, nil
+}
+
+// ImportOptionsForRepository creates a copy of the import options to import the given repository which does not share
+// any state with the other repositories. The --config file is merged with the configuration in each repository
+func (o *ImportOptions) ImportOptionsForRepository(r *scm.Repository) *ImportOptions {
+	o2 := *o
+	o2.GitHub = false
+	o2.RepoURL = r.Clone
+	if o2.RepoURL == "" {
+		o2.RepoURL = r.Link
+	}
+	o2.Repository = r.Name
+	o2.AppName = ""
+	o2.DiscoveredGitURL = ""
+	o2.GitConfDir = ""
+	o2.InitialisedGit = false
+	o2.OnCompleteCallback = nil
+	o2.gitInfo = nil
+	o2.GitRepositoryOptions.Name = r.Name
+	o2.state = nil
+	o2.reporter = nil
+	o2.githubAppMode = nil
+	o2.projectConfig = nil
+	o2.parentProjectConfig = o.projectConfig
+	o2.ProjectConfigFile = ""
+	o.DeployOptions.DeepCopyInto(&o2.DeployOptions)
+
+	// the pack is chosen for each repository unless it was specified on the command line
+	if !o.FlagChanged("pack") {
+		o2.Pack = ""
+	}
+	if !o.FlagChanged("no-pack") {
+		o2.DisableBuildPack = false
+	}
+	return &o2
+}
+
+func (o *ImportOptions) reportImportProjectResults(results []*ImportProjectResult) error {
+	failed := 0
+	log.Logger().Info("")
+	log.Logger().Info("Import summary:")
+	for _, r := range results {
+		if r.Error != nil {
+			failed++
+			log.Logger().Infof("  %s %s: %s", termcolor.ColorError("FAILED"), r.Name, r.Error.Error())
+		} else {
+			log.Logger().Infof("  %s %s", termcolor.ColorInfo("OK"), r.Name)
+		}
+	}
+	log.Logger().Info("")
+	if failed > 0 {
+		return fmt.Errorf("failed to import %d of %d repositories", failed, len(results))
+	}
+	log.Logger().Infof("imported %d repositories", len(results))
+	return nil
+}
@@ -0,0 +1,107 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/testimports"
+	"github.com/jenkins-x/go-scm/scm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPickRepositories(t *testing.T) {
+	o := &importcmd.ImportOptions{}
+	fakeScmData, _, _ := testimports.SetFakeClients(t, o, false)
+	o.ScmFactory.GitUsername = fakeScmData.CurrentUser.Login
+	o.Organisation = fakeScmData.CurrentUser.Login
+	o.BatchMode = true
+	o.SelectAll = true
+	o.SelectFilter = "foo"
+
+	for _, name := range []string{"foo-service", "bar", "another-foo", "old-foo"} {
+		fakeScmData.Repositories = append(fakeScmData.Repositories, &scm.Repository{
+			Namespace: o.Organisation,
+			Name:      name,
+			FullName:  scm.Join(o.Organisation, name),
+			Clone:     "https://github.com/" + scm.Join(o.Organisation, name) + ".git",
+			Archived:  name == "old-foo",
+		})
+	}
+
+	repos, err := o.PickRepositories()
+	require.NoError(t, err, "failed to pick repositories")
+
+	var names []string
+	for _, r := range repos {
+		names = append(names, r.Name)
+	}
+	assert.Equal(t, []string{"another-foo", "foo-service"}, names, "selected repositories")
+
+	o.SelectAll = false
+	_, err = o.PickRepositories()
+	assert.Error(t, err, "should fail in batch mode without --all")
+}
+
+func TestImportOptionsForRepository(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	repoDir := t.TempDir()
+	writeTestFiles(t, filepath.Dir(configFile), map[string]string{
+		"config.yaml": "deployKind: knative\ncanary: true\nplaceholders:\n  REPLACE_ME_A: a\n",
+	})
+	writeTestFiles(t, repoDir, map[string]string{
+		".jx/project.yaml": "pack: maven\ncanary: false\nplaceholders:\n  REPLACE_ME_B: b\n",
+	})
+
+	o := &importcmd.ImportOptions{}
+	testimports.SetFakeClients(t, o, false)
+	o.GitHub = true
+	o.ProjectConfigFile = configFile
+	config, err := o.LoadProjectConfig("")
+	require.NoError(t, err, "failed to load the --config file")
+	require.NoError(t, o.ApplyProjectConfig(config), "failed to apply the --config file")
+	o.SetReporter(importcmd.NewJSONImportReporter(&bytes.Buffer{}))
+
+	o2 := o.ImportOptionsForRepository(&scm.Repository{Name: "myrepo", Clone: "https://github.com/myorg/myrepo.git"})
+	assert.False(t, o2.GitHub, "o2.GitHub")
+	assert.Empty(t, o2.ProjectConfigFile, "o2.ProjectConfigFile")
+	assert.IsType(t, &importcmd.LogImportReporter{}, o2.GetReporter(), "the reporter should not be shared")
+
+	config, err = o2.LoadProjectConfig(repoDir)
+	require.NoError(t, err, "failed to load the repository configuration")
+	require.NotNil(t, config, "config")
+	assert.Equal(t, "knative", config.DeployKind, "the --config value should be used")
+	assert.Equal(t, "maven", config.Pack, "the repository value should be used")
+	require.NotNil(t, config.Canary, "config.Canary")
+	assert.False(t, *config.Canary, "the repository value should take precedence")
+	assert.Equal(t, map[string]string{"REPLACE_ME_A": "a", "REPLACE_ME_B": "b"}, config.Placeholders, "config.Placeholders")
+
+	require.NoError(t, o2.ApplyProjectConfig(config), "failed to apply the repository configuration")
+	assert.False(t, o2.DeployOptions.Canary, "o2.DeployOptions.Canary")
+	assert.True(t, o.DeployOptions.Canary, "the parent options should not change")
+
+	config, err = o.LoadProjectConfig("")
+	require.NoError(t, err, "failed to reload the --config file")
+	assert.Empty(t, config.Pack, "the parent configuration should not change")
+}
+
+func TestImportProjectsDoesNotReportParentCompleted(t *testing.T) {
+	o := &importcmd.ImportOptions{}
+	fakeScmData, _, _ := testimports.SetFakeClients(t, o, false)
+	o.ScmFactory.GitUsername = fakeScmData.CurrentUser.Login
+	o.Organisation = fakeScmData.CurrentUser.Login
+	o.GitHub = true
+	o.BatchMode = true
+	o.SelectAll = true
+	buf := &bytes.Buffer{}
+	o.SetReporter(importcmd.NewJSONImportReporter(buf))
+
+	err := o.Run()
+	require.Error(t, err, "should fail without any repositories")
+	assert.NotContains(t, buf.String(), importcmd.EventCompleted, "the organisation import should not report a completed event")
+}
@@ -1,6 +1,8 @@
 package importcmd
 
 import (
+	"fmt"
+
 	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
 	"github.com/jenkins-x/jx-logging/v3/pkg/log"
 )
@@ -15,8 +17,20 @@ type ImportReporter interface {
 	PushedGitRepository(url string)
 	// GitRepositoryCreated report progress
 	GitRepositoryCreated()
+	// CreatedRemoteRepository report progress
+	CreatedRemoteRepository(repoURL string)
+	// AddedCollaborator report progress
+	AddedCollaborator(repository string, username string)
 	// CreatedDevRepoPullRequest report progress
 	CreatedDevRepoPullRequest(prURL string, devGitURL string)
+	// ResumedDevRepoPullRequest report progress
+	ResumedDevRepoPullRequest(prURL string, devGitURL string)
+	// MergedDevRepoPullRequest report progress
+	MergedDevRepoPullRequest(prURL string, mergeSha string)
+	// StartedPipeline report progress
+	StartedPipeline(repository string)
+	// Completed reports the final result of importing the repository
+	Completed(repository string, repoURL string, err error)
 	// CreatedProject report progress
 	CreatedProject(genDir string)
 	// GeneratedQuickStartAt report progress
@@ -42,6 +56,47 @@ func (r *LogImportReporter) CreatedDevRepoPullRequest(prURL, devGitURL string) {
 	log.Logger().Debugf("Created pull request %s on the development git repository %s", info(prURL), info(devGitURL))
 }
 
+// ResumedDevRepoPullRequest report progress
+func (r *LogImportReporter) ResumedDevRepoPullRequest(prURL, devGitURL string) {
+	log.Logger().Debugf("Resumed pull request %s on the development git repository %s", info(prURL), info(devGitURL))
+}
+
+// CreatedRemoteRepository report progress
+func (r *LogImportReporter) CreatedRemoteRepository(repoURL string) {
+	log.Logger().Infof("Created git repository %s", info(repoURL))
+}
+
+// AddedCollaborator report progress
+func (r *LogImportReporter) AddedCollaborator(repository, username string) {
+	log.Logger().Infof("Added %s as a collaborator to %s", info(username), info(repository))
+}
+
+// MergedDevRepoPullRequest report progress
+func (r *LogImportReporter) MergedDevRepoPullRequest(prURL, mergeSha string) {
+	log.Logger().Debugf("Merged pull request %s at sha %s", info(prURL), info(mergeSha))
+}
+
+// StartedPipeline report progress
+func (r *LogImportReporter) StartedPipeline(repository string) {
+	log.Logger().Info("")
+	log.Logger().Infof("Pipeline should start soon for: %s", info(repository))
+	log.Logger().Info("")
+	log.Logger().Infof("Watch pipeline activity via:    %s", info(fmt.Sprintf("jx get activity -f %s -w", repository)))
+	log.Logger().Infof("Browse the pipeline log via:    %s", info(fmt.Sprintf("jx get build logs %s", repository)))
+	log.Logger().Infof("You can list the pipelines via: %s", info("jx get pipelines"))
+	log.Logger().Infof("When the pipeline is complete:  %s", info("jx get applications"))
+	log.Logger().Info("")
+}
+
+// Completed reports the final result of importing the repository
+func (r *LogImportReporter) Completed(repository, repoURL string, err error) {
+	if err != nil {
+		log.Logger().Debugf("failed to import %s: %s", repository, err.Error())
+		return
+	}
+	log.Logger().Debugf("imported %s from %s", repository, repoURL)
+}
+
 // GitRepositoryCreated report progress
 func (r *LogImportReporter) GitRepositoryCreated() {
 	log.Logger().Infof("\nGit repository created")
@@ -0,0 +1,173 @@
+package importcmd
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+)
+
+const (
+	// EventUsingGitUserName the git user name used for the import
+	EventUsingGitUserName = "using-git-user"
+	// EventGitRepositoryCreated the local git repository was initialised
+	EventGitRepositoryCreated = "git-repository-created"
+	// EventRemoteRepositoryCreated the repository was created on the git provider
+	EventRemoteRepositoryCreated = "repository-created"
+	// EventRepositoryPushed the source code was pushed to the repository on the git provider
+	EventRepositoryPushed = "repository-pushed"
+	// EventCollaboratorAdded the pipeline user was added as a collaborator to the repository
+	EventCollaboratorAdded = "collaborator-added"
+	// EventDevPullRequestCreated the Pull Request on the cluster git repository was created
+	EventDevPullRequestCreated = "dev-pull-request-created"
+	// EventDevPullRequestResumed the Pull Request on the cluster git repository was created by a previous import
+	EventDevPullRequestResumed = "dev-pull-request-resumed"
+	// EventDevPullRequestMerged the Pull Request on the cluster git repository was merged
+	EventDevPullRequestMerged = "dev-pull-request-merged"
+	// EventPipelineStarted the release pipeline of the repository was triggered
+	EventPipelineStarted = "pipeline-started"
+	// EventProjectCreated a new project was created from a quickstart
+	EventProjectCreated = "project-created"
+	// EventQuickStartGenerated the quickstart was generated into a directory
+	EventQuickStartGenerated = "quickstart-generated"
+	// EventCompleted the import finished
+	EventCompleted = "completed"
+
+	// ResultSucceeded the import succeeded
+	ResultSucceeded = "succeeded"
+	// ResultFailed the import failed
+	ResultFailed = "failed"
+)
+
+// ImportEvent an event emitted by the JSONImportReporter. Fields which do not apply to an event are omitted
+type ImportEvent struct {
+	// Time when the event occurred
+	Time time.Time `json:"time"`
+	// Event the kind of event such as repository-created
+	Event string `json:"event"`
+	// Repository the full name of the repository such as owner/name
+	Repository string `json:"repository,omitempty"`
+	// URL the URL of the repository or Pull Request
+	URL string `json:"url,omitempty"`
+	// DevGitURL the URL of the cluster git repository
+	DevGitURL string `json:"devGitUrl,omitempty"`
+	// User the git user name
+	User string `json:"user,omitempty"`
+	// Dir the local directory
+	Dir string `json:"dir,omitempty"`
+	// SHA the git commit sha
+	SHA string `json:"sha,omitempty"`
+	// Result the result of the import for the completed event which is either succeeded or failed
+	Result string `json:"result,omitempty"`
+	// Error the error message if the import failed
+	Error string `json:"error,omitempty"`
+}
+
+var _ ImportReporter = &JSONImportReporter{}
+
+// JSONImportReporter writes each update as a newline delimited JSON ImportEvent so that tools can process them
+type JSONImportReporter struct {
+	Out io.Writer
+	// Now returns the time of an event. Defaults to time.Now
+	Now func() time.Time
+
+	lock sync.Mutex
+}
+
+// NewJSONImportReporter creates a reporter which writes JSON events to the given writer
+func NewJSONImportReporter(out io.Writer) *JSONImportReporter {
+	return &JSONImportReporter{Out: out}
+}
+
+// Trace generic trace messages are not included in the JSON events
+func (r *JSONImportReporter) Trace(message string, args ...interface{}) {
+	log.Logger().Debugf(message, args...)
+}
+
+// UsingGitUserName report progress
+func (r *JSONImportReporter) UsingGitUserName(username string) {
+	r.write(&ImportEvent{Event: EventUsingGitUserName, User: username})
+}
+
+// GitRepositoryCreated report progress
+func (r *JSONImportReporter) GitRepositoryCreated() {
+	r.write(&ImportEvent{Event: EventGitRepositoryCreated})
+}
+
+// CreatedRemoteRepository report progress
+func (r *JSONImportReporter) CreatedRemoteRepository(repoURL string) {
+	r.write(&ImportEvent{Event: EventRemoteRepositoryCreated, URL: repoURL})
+}
+
+// PushedGitRepository report progress
+func (r *JSONImportReporter) PushedGitRepository(repoURL string) {
+	r.write(&ImportEvent{Event: EventRepositoryPushed, URL: repoURL})
+}
+
+// AddedCollaborator report progress
+func (r *JSONImportReporter) AddedCollaborator(repository, username string) {
+	r.write(&ImportEvent{Event: EventCollaboratorAdded, Repository: repository, User: username})
+}
+
+// CreatedDevRepoPullRequest report progress
+func (r *JSONImportReporter) CreatedDevRepoPullRequest(prURL, devGitURL string) {
+	r.write(&ImportEvent{Event: EventDevPullRequestCreated, URL: prURL, DevGitURL: devGitURL})
+}
+
+// ResumedDevRepoPullRequest report progress
+func (r *JSONImportReporter) ResumedDevRepoPullRequest(prURL, devGitURL string) {
+	r.write(&ImportEvent{Event: EventDevPullRequestResumed, URL: prURL, DevGitURL: devGitURL})
+}
+
+// MergedDevRepoPullRequest report progress
+func (r *JSONImportReporter) MergedDevRepoPullRequest(prURL, mergeSha string) {
+	r.write(&ImportEvent{Event: EventDevPullRequestMerged, URL: prURL, SHA: mergeSha})
+}
+
+// StartedPipeline report progress
+func (r *JSONImportReporter) StartedPipeline(repository string) {
+	r.write(&ImportEvent{Event: EventPipelineStarted, Repository: repository})
+}
+
+// Completed reports the final result of importing the repository
+func (r *JSONImportReporter) Completed(repository, repoURL string, err error) {
+	e := &ImportEvent{Event: EventCompleted, Repository: repository, URL: repoURL, Result: ResultSucceeded}
+	if err != nil {
+		e.Result = ResultFailed
+		e.Error = err.Error()
+	}
+	r.write(e)
+}
+
+// CreatedProject report progress
+func (r *JSONImportReporter) CreatedProject(genDir string) {
+	r.write(&ImportEvent{Event: EventProjectCreated, Dir: genDir})
+}
+
+// GeneratedQuickStartAt report progress
+func (r *JSONImportReporter) GeneratedQuickStartAt(genDir string) {
+	r.write(&ImportEvent{Event: EventQuickStartGenerated, Dir: genDir})
+}
+
+func (r *JSONImportReporter) write(e *ImportEvent) {
+	if r.Now != nil {
+		e.Time = r.Now()
+	} else {
+		e.Time = time.Now().UTC()
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		log.Logger().Warnf("failed to marshal import event %s: %s", e.Event, err.Error())
+		return
+	}
+	data = append(data, '\n')
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	_, err = r.Out.Write(data)
+	if err != nil {
+		log.Logger().Warnf("failed to write import event %s: %s", e.Event, err.Error())
+	}
+}
@@ -0,0 +1,147 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/testimports"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cmdrunner"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONImportReporter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	now := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	r := importcmd.NewJSONImportReporter(buf)
+	r.Now = func() time.Time {
+		return now
+	}
+
+	r.Trace("should not be %s", "reported")
+	r.CreatedRemoteRepository("https://github.com/myorg/myapp")
+	r.PushedGitRepository("https://github.com/myorg/myapp")
+	r.AddedCollaborator("myorg/myapp", "mybot")
+	r.CreatedDevRepoPullRequest("https://github.com/myorg/cluster/pull/1", "https://github.com/myorg/cluster")
+	r.ResumedDevRepoPullRequest("https://github.com/myorg/cluster/pull/1", "https://github.com/myorg/cluster")
+	r.MergedDevRepoPullRequest("https://github.com/myorg/cluster/pull/1", "abc123")
+	r.StartedPipeline("myorg/myapp")
+	r.Completed("myorg/myapp", "https://github.com/myorg/myapp", nil)
+	r.Completed("myorg/another", "", errors.New("boom"))
+
+	var events []importcmd.ImportEvent
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		e := importcmd.ImportEvent{}
+		err := json.Unmarshal(scanner.Bytes(), &e)
+		require.NoError(t, err, "failed to parse event %s", scanner.Text())
+		assert.Equal(t, now, e.Time, "time of event %s", e.Event)
+		events = append(events, e)
+	}
+
+	expected := []importcmd.ImportEvent{
+		{Time: now, Event: importcmd.EventRemoteRepositoryCreated, URL: "https://github.com/myorg/myapp"},
+		{Time: now, Event: importcmd.EventRepositoryPushed, URL: "https://github.com/myorg/myapp"},
+		{Time: now, Event: importcmd.EventCollaboratorAdded, Repository: "myorg/myapp", User: "mybot"},
+		{Time: now, Event: importcmd.EventDevPullRequestCreated, URL: "https://github.com/myorg/cluster/pull/1", DevGitURL: "https://github.com/myorg/cluster"},
+		{Time: now, Event: importcmd.EventDevPullRequestResumed, URL: "https://github.com/myorg/cluster/pull/1", DevGitURL: "https://github.com/myorg/cluster"},
+		{Time: now, Event: importcmd.EventDevPullRequestMerged, URL: "https://github.com/myorg/cluster/pull/1", SHA: "abc123"},
+		{Time: now, Event: importcmd.EventPipelineStarted, Repository: "myorg/myapp"},
+		{Time: now, Event: importcmd.EventCompleted, Repository: "myorg/myapp", URL: "https://github.com/myorg/myapp", Result: importcmd.ResultSucceeded},
+		{Time: now, Event: importcmd.EventCompleted, Repository: "myorg/another", Result: importcmd.ResultFailed, Error: "boom"},
+	}
+	assert.Equal(t, expected, events)
+}
+
+func TestImportOutputInvalidFormat(t *testing.T) {
+	o := &importcmd.ImportOptions{}
+	o.OutputFormat = "xml"
+
+	err := o.Validate()
+	require.Error(t, err, "should fail for an unsupported output format")
+	assert.Contains(t, err.Error(), "xml")
+}
+
+func TestJSONOutputOnlyWritesEventsToStdout(t *testing.T) {
+	dir := t.TempDir()
+	err := files.CopyDirOverwrite(filepath.Join("test_data", "monorepo", "services", "cheese"), dir)
+	require.NoError(t, err, "failed to copy source")
+
+	o := &importcmd.ImportOptions{}
+	testimports.SetFakeClients(t, o, false)
+	o.Dir = dir
+	o.AppName = "cheese"
+	o.Organisation = "myorg"
+	o.BatchMode = true
+	o.OutputFormat = "json"
+	o.PipelineCatalogDir = t.TempDir()
+	writeTestFiles(t, o.PipelineCatalogDir, map[string]string{
+		"go/Dockerfile": "FROM scratch\n",
+	})
+	o.CommandRunner = func(c *cmdrunner.Command) (string, error) {
+		if c.Out != nil {
+			fmt.Fprintf(c.Out, "faking command: %s\n", c.CLI())
+		}
+		return "", nil
+	}
+
+	stdout := captureStdout(t, func() {
+		err = o.Validate()
+		require.NoError(t, err, "failed to validate")
+
+		err = o.EvaluateBuildPack(t.TempDir(), "")
+		require.NoError(t, err, "failed to evaluate the pack")
+
+		err = o.WaitForPipeline("cheese")
+		require.NoError(t, err, "failed to wait for the pipeline")
+
+		o.GetReporter().StartedPipeline("myorg/cheese")
+	})
+
+	lines := 0
+	scanner := bufio.NewScanner(bytes.NewBufferString(stdout))
+	for scanner.Scan() {
+		e := importcmd.ImportEvent{}
+		err = json.Unmarshal(scanner.Bytes(), &e)
+		require.NoError(t, err, "stdout line should be a JSON event: %s", scanner.Text())
+		lines++
+	}
+	assert.Equal(t, 1, lines, "stdout: %s", stdout)
+}
+
+// captureStdout returns what the function writes to os.Stdout
+func captureStdout(t *testing.T, fn func()) string {
+	r, w, err := os.Pipe()
+	require.NoError(t, err, "failed to create pipe")
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	buf := &bytes.Buffer{}
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(buf, r)
+		close(done)
+	}()
+
+	fn()
+	require.NoError(t, w.Close(), "failed to close pipe")
+	<-done
+	return buf.String()
+}
@@ -0,0 +1,227 @@
+package importcmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/yamls"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ImportStateFileName the name of the file in the .jx directory which records the completed import steps
+	ImportStateFileName = "import-state.yaml"
+
+	// StepDiscoverGit the local git repository has been found or initialised
+	StepDiscoverGit = "discover-git"
+	// StepBuildPack the pipeline catalog pack has been applied to the source code
+	StepBuildPack = "build-pack"
+	// StepCreateRepository the remote git repository has been created and pushed to
+	StepCreateRepository = "create-repository"
+	// StepAddCollaborator the pipeline user has been added as a collaborator
+	StepAddCollaborator = "add-collaborator"
+	// StepDevPullRequest the Pull Request on the dev environment git repository has been created
+	StepDevPullRequest = "dev-pull-request"
+	// StepWaitDevPullRequest the Pull Request on the dev environment git repository has merged
+	StepWaitDevPullRequest = "wait-dev-pull-request"
+	// StepWaitPipeline the pipeline has been setup for the repository
+	StepWaitPipeline = "wait-pipeline"
+	// StepPush the pipeline catalog changes have been pushed
+	StepPush = "push"
+)
+
+// ImportState the journal of the import steps which have completed so that an import can be resumed
+type ImportState struct {
+	// Steps the steps which have completed in order
+	Steps []ImportStep `json:"steps,omitempty"`
+	// DisableBuildPack whether the pipeline catalog pack was disabled
+	DisableBuildPack bool `json:"disableBuildPack,omitempty"`
+	// CreatedRepository whether the import created the remote git repository
+	CreatedRepository bool `json:"createdRepository,omitempty"`
+	// RepositoryURL the git URL of the imported repository
+	RepositoryURL string `json:"repositoryUrl,omitempty"`
+	// PullRequestURL the URL of the Pull Request on the dev environment git repository
+	PullRequestURL string `json:"pullRequestUrl,omitempty"`
+	// PullRequestRepository the full name of the dev environment git repository
+	PullRequestRepository string `json:"pullRequestRepository,omitempty"`
+	// PullRequestNumber the number of the Pull Request on the dev environment git repository
+	PullRequestNumber int `json:"pullRequestNumber,omitempty"`
+	// RemoteCluster whether the repository is imported into a remote cluster
+	RemoteCluster bool `json:"remoteCluster,omitempty"`
+}
+
+// ImportStep a completed step of an import
+type ImportStep struct {
+	Name      string    `json:"name"`
+	Completed time.Time `json:"completed"`
+}
+
+// ImportStateFile returns the path of the import state file in the given directory
+func ImportStateFile(dir string) string {
+	return filepath.Join(dir, ".jx", ImportStateFileName)
+}
+
+// LoadImportState loads the import state from the given directory returning an empty state if there is none
+func LoadImportState(dir string) (*ImportState, error) {
+	state := &ImportState{}
+	path := ImportStateFile(dir)
+	exists, err := files.FileExists(path)
+	if err != nil {
+		return state, errors.Wrapf(err, "failed to check if file exists %s", path)
+	}
+	if !exists {
+		return state, nil
+	}
+	err = yamls.LoadFile(path, state)
+	if err != nil {
+		return state, errors.Wrapf(err, "failed to load file %s", path)
+	}
+	return state, nil
+}
+
+// SaveImportState saves the import state in the given directory making sure git ignores it
+func SaveImportState(dir string, state *ImportState) error {
+	path := ImportStateFile(dir)
+	err := os.MkdirAll(filepath.Dir(path), files.DefaultDirWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create dir %s", filepath.Dir(path))
+	}
+	err = yamls.SaveFile(state, path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save file %s", path)
+	}
+	return excludeFromGit(dir, "/.jx/"+ImportStateFileName)
+}
+
+// IsCompleted returns true if the given step has completed
+func (s *ImportState) IsCompleted(name string) bool {
+	for _, step := range s.Steps {
+		if step.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Complete marks the given step as completed
+func (s *ImportState) Complete(name string) {
+	if s.IsCompleted(name) {
+		return
+	}
+	s.Steps = append(s.Steps, ImportStep{
+		Name:      name,
+		Completed: time.Now().UTC(),
+	})
+}
+
+// excludeFromGit adds the pattern to the .git/info/exclude file so the file is never committed
+func excludeFromGit(dir, pattern string) error {
+	gitDir := filepath.Join(dir, ".git")
+	exists, err := files.DirExists(gitDir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if dir exists %s", gitDir)
+	}
+	if !exists {
+		return nil
+	}
+	path := filepath.Join(gitDir, "info", "exclude")
+	var text string
+	exists, err = files.FileExists(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if file exists %s", path)
+	}
+	if exists {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read file %s", path)
+		}
+		text = string(data)
+		for _, line := range strings.Split(text, "\n") {
+			if strings.TrimSpace(line) == pattern {
+				return nil
+			}
+		}
+		if text != "" && !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+	}
+	text += pattern + "\n"
+	err = os.MkdirAll(filepath.Dir(path), files.DefaultDirWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create dir %s", filepath.Dir(path))
+	}
+	err = os.WriteFile(path, []byte(text), files.DefaultFileWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save file %s", path)
+	}
+	return nil
+}
+
+// loadImportState loads the previous import state if resuming or starts a new one
+func (o *ImportOptions) loadImportState() error {
+	if o.DryRun {
+		return nil
+	}
+	path := ImportStateFile(o.Dir)
+	if !o.Resume {
+		exists, err := files.FileExists(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to check if file exists %s", path)
+		}
+		if exists {
+			log.Logger().Infof("ignoring the previous import state in %s. Use %s to resume the previous import", path, termcolor.ColorInfo("--resume"))
+		}
+		o.state = &ImportState{}
+		return nil
+	}
+	var err error
+	o.state, err = LoadImportState(o.Dir)
+	if err != nil {
+		return err
+	}
+	for _, step := range o.state.Steps {
+		log.Logger().Infof("resuming import: step %s completed at %s", termcolor.ColorInfo(step.Name), step.Completed.Local().Format(time.RFC822))
+	}
+	return nil
+}
+
+// isStepCompleted returns true if we are resuming an import and the step has already completed
+func (o *ImportOptions) isStepCompleted(name string) bool {
+	return o.Resume && o.state != nil && o.state.IsCompleted(name)
+}
+
+// completeStep records the step as completed in the import state
+func (o *ImportOptions) completeStep(name string) error {
+	if o.state == nil {
+		return nil
+	}
+	o.state.Complete(name)
+	return o.saveImportState()
+}
+
+func (o *ImportOptions) saveImportState() error {
+	if o.state == nil {
+		return nil
+	}
+	return SaveImportState(o.Dir, o.state)
+}
+
+// removeImportState removes the import state once the import has completed
+func (o *ImportOptions) removeImportState() error {
+	if o.state == nil {
+		return nil
+	}
+	path := ImportStateFile(o.Dir)
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to remove %s", path)
+	}
+	// lets remove the .jx directory if it is now empty
+	_ = os.Remove(filepath.Dir(path))
+	return nil
+}
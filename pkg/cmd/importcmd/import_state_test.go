@@ -0,0 +1,52 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportState(t *testing.T) {
+	dir := t.TempDir()
+	err := os.MkdirAll(filepath.Join(dir, ".git", "info"), 0o755)
+	require.NoError(t, err, "failed to create .git dir")
+
+	state, err := importcmd.LoadImportState(dir)
+	require.NoError(t, err, "failed to load missing state")
+	assert.Empty(t, state.Steps, "state.Steps")
+
+	state.Complete(importcmd.StepDiscoverGit)
+	state.Complete(importcmd.StepCreateRepository)
+	state.Complete(importcmd.StepDiscoverGit)
+	state.CreatedRepository = true
+	state.RepositoryURL = "https://github.com/myorg/myapp"
+	state.PullRequestNumber = 12
+
+	// save twice to check the git exclude is only added once
+	for i := 0; i < 2; i++ {
+		err = importcmd.SaveImportState(dir, state)
+		require.NoError(t, err, "failed to save state")
+	}
+
+	loaded, err := importcmd.LoadImportState(dir)
+	require.NoError(t, err, "failed to load state")
+	require.Len(t, loaded.Steps, 2, "loaded.Steps")
+	assert.True(t, loaded.IsCompleted(importcmd.StepDiscoverGit), "should have completed %s", importcmd.StepDiscoverGit)
+	assert.True(t, loaded.IsCompleted(importcmd.StepCreateRepository), "should have completed %s", importcmd.StepCreateRepository)
+	assert.False(t, loaded.IsCompleted(importcmd.StepDevPullRequest), "should not have completed %s", importcmd.StepDevPullRequest)
+	assert.True(t, loaded.CreatedRepository, "loaded.CreatedRepository")
+	assert.Equal(t, "https://github.com/myorg/myapp", loaded.RepositoryURL, "loaded.RepositoryURL")
+	assert.Equal(t, 12, loaded.PullRequestNumber, "loaded.PullRequestNumber")
+
+	data, err := os.ReadFile(filepath.Join(dir, ".git", "info", "exclude"))
+	require.NoError(t, err, "failed to read git exclude file")
+	assert.Equal(t, 1, strings.Count(string(data), "/.jx/"+importcmd.ImportStateFileName), "git exclude file: %s", string(data))
+}
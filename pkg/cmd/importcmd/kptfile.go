@@ -0,0 +1,199 @@
+package importcmd
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/yamls"
+	"github.com/pkg/errors"
+)
+
+const (
+	// KptfileName the name of the file which records the upstream of a .lighthouse/<name> folder
+	KptfileName = "Kptfile"
+
+	// KptAPIVersion the current kpt API version of Kptfile files
+	KptAPIVersion = "kpt.dev/v1"
+
+	// KptAPIVersionV1Alpha1 the deprecated kpt API version which recorded the commit in upstream.git
+	KptAPIVersionV1Alpha1 = "kpt.dev/v1alpha1"
+
+	// KptUpdateStrategy the strategy kpt uses to merge upstream changes
+	KptUpdateStrategy = "resource-merge"
+)
+
+// Kptfile the kpt file recording where a pipeline folder was copied from
+type Kptfile struct {
+	APIVersion string          `json:"apiVersion,omitempty"`
+	Kind       string          `json:"kind,omitempty"`
+	Metadata   KptfileMetadata `json:"metadata,omitempty"`
+	Upstream   *KptUpstream    `json:"upstream,omitempty"`
+	// UpstreamLock the resolved commit of the upstream used by kpt v1
+	UpstreamLock *KptUpstream `json:"upstreamLock,omitempty"`
+}
+
+// KptfileMetadata the metadata of a Kptfile
+type KptfileMetadata struct {
+	Name string `json:"name,omitempty"`
+}
+
+// KptUpstream the upstream source of a Kptfile
+type KptUpstream struct {
+	Type           string  `json:"type,omitempty"`
+	Git            *KptGit `json:"git,omitempty"`
+	UpdateStrategy string  `json:"updateStrategy,omitempty"`
+}
+
+// KptGit the git repository, directory and ref of the upstream source. The commit is only used by the upstream lock
+// and the deprecated v1alpha1 upstream
+type KptGit struct {
+	Commit    string `json:"commit,omitempty"`
+	Repo      string `json:"repo,omitempty"`
+	Directory string `json:"directory,omitempty"`
+	Ref       string `json:"ref,omitempty"`
+}
+
+// LoadKptfile loads the Kptfile at the given path
+func LoadKptfile(path string) (*Kptfile, error) {
+	kf := &Kptfile{}
+	err := yamls.LoadFile(path, kf)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load Kptfile %s", path)
+	}
+	return kf, nil
+}
+
+// NewKptfile returns a kpt v1 Kptfile for the upstream git repository, directory and ref locked at the commit
+func NewKptfile(name, repo, directory, ref, commit string) *Kptfile {
+	return &Kptfile{
+		APIVersion: KptAPIVersion,
+		Kind:       "Kptfile",
+		Metadata: KptfileMetadata{
+			Name: name,
+		},
+		Upstream: &KptUpstream{
+			Type: "git",
+			Git: &KptGit{
+				Repo:      repo,
+				Directory: directory,
+				Ref:       ref,
+			},
+			UpdateStrategy: KptUpdateStrategy,
+		},
+		UpstreamLock: &KptUpstream{
+			Type: "git",
+			Git: &KptGit{
+				Repo:      repo,
+				Directory: directory,
+				Ref:       ref,
+				Commit:    commit,
+			},
+		},
+	}
+}
+
+// SaveKptfile saves the Kptfile to the given path
+func SaveKptfile(kf *Kptfile, path string) error {
+	err := yamls.SaveFile(kf, path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save Kptfile %s", path)
+	}
+	return nil
+}
+
+// GitUpstream returns the git upstream of the Kptfile with the locked commit or nil if it has none
+func (k *Kptfile) GitUpstream() *KptGit {
+	if k.Upstream == nil || k.Upstream.Git == nil || k.Upstream.Git.Repo == "" {
+		return nil
+	}
+	answer := *k.Upstream.Git
+	if k.UpstreamLock != nil && k.UpstreamLock.Git != nil && k.UpstreamLock.Git.Commit != "" {
+		answer.Commit = k.UpstreamLock.Git.Commit
+	}
+	return &answer
+}
+
+// SetGitCommit locks the upstream to the commit
+func (k *Kptfile) SetGitCommit(commit string) {
+	u := k.GitUpstream()
+	if u == nil {
+		return
+	}
+	if k.APIVersion == KptAPIVersionV1Alpha1 {
+		k.Upstream.Git.Commit = commit
+		return
+	}
+	u.Commit = commit
+	k.UpstreamLock = &KptUpstream{
+		Type: "git",
+		Git:  u,
+	}
+}
+
+// Migrate converts a deprecated v1alpha1 Kptfile to kpt v1 returning true if it was changed
+func (k *Kptfile) Migrate() bool {
+	if k.APIVersion != KptAPIVersionV1Alpha1 {
+		return false
+	}
+	k.APIVersion = KptAPIVersion
+	u := k.GitUpstream()
+	if u == nil {
+		return true
+	}
+	k.Upstream.Git.Commit = ""
+	if k.Upstream.UpdateStrategy == "" {
+		k.Upstream.UpdateStrategy = KptUpdateStrategy
+	}
+	k.SetGitCommit(u.Commit)
+	return true
+}
+
+// MigrateLighthouseKptfiles rewrites the deprecated v1alpha1 Kptfile files of the .lighthouse/<name> folders of the
+// directory as kpt v1 returning the paths of the migrated files
+func MigrateLighthouseKptfiles(dir string) ([]string, error) {
+	paths, err := FindLighthouseKptfiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	var answer []string
+	for _, path := range paths {
+		kf, err := LoadKptfile(path)
+		if err != nil {
+			return answer, err
+		}
+		if !kf.Migrate() {
+			continue
+		}
+		err = SaveKptfile(kf, path)
+		if err != nil {
+			return answer, err
+		}
+		answer = append(answer, path)
+	}
+	return answer, nil
+}
+
+// FindLighthouseKptfiles returns the paths of the Kptfile files of the .lighthouse/<name> folders of the directory
+func FindLighthouseKptfiles(dir string) ([]string, error) {
+	g := filepath.Join(dir, ".lighthouse", "*", KptfileName)
+	matches, err := filepath.Glob(g)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to evaluate glob %s", g)
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// UpstreamGitURL returns the git URL to clone the upstream repository
+func (g *KptGit) UpstreamGitURL() string {
+	if strings.Contains(g.Repo, "://") && !strings.HasSuffix(g.Repo, ".git") {
+		return g.Repo + ".git"
+	}
+	return g.Repo
+}
+
+// UpstreamDirectory returns the directory in the upstream repository relative to its root
+func (g *KptGit) UpstreamDirectory() string {
+	return strings.Trim(filepath.ToSlash(g.Directory), "/")
+}
@@ -0,0 +1,72 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateLighthouseKptfiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	err := files.CopyDirOverwrite(filepath.Join("test_data", "remote-cluster", ".lighthouse"), filepath.Join(tmpDir, ".lighthouse"))
+	require.NoError(t, err, "failed to copy test data")
+
+	path := filepath.Join(tmpDir, ".lighthouse", "jenkins-x", importcmd.KptfileName)
+	migrated, err := importcmd.MigrateLighthouseKptfiles(tmpDir)
+	require.NoError(t, err, "failed to migrate Kptfiles")
+	assert.Equal(t, []string{path}, migrated)
+
+	kf, err := importcmd.LoadKptfile(path)
+	require.NoError(t, err)
+	assert.Equal(t, importcmd.KptAPIVersion, kf.APIVersion)
+	require.NotNil(t, kf.Upstream)
+	require.NotNil(t, kf.Upstream.Git)
+	assert.Empty(t, kf.Upstream.Git.Commit, "the commit should be moved to the upstreamLock")
+	assert.Equal(t, "master", kf.Upstream.Git.Ref)
+	assert.Equal(t, importcmd.KptUpdateStrategy, kf.Upstream.UpdateStrategy)
+	require.NotNil(t, kf.UpstreamLock)
+	require.NotNil(t, kf.UpstreamLock.Git)
+	assert.Equal(t, &importcmd.KptGit{
+		Commit:    "e101bd0bcd17ca317346c5dfaaa1dd041a305c0a",
+		Repo:      "https://github.com/jenkins-x/jx3-pipeline-catalog",
+		Directory: "/environment/.lighthouse/jenkins-x",
+		Ref:       "master",
+	}, kf.UpstreamLock.Git)
+	assert.Equal(t, kf.UpstreamLock.Git, kf.GitUpstream())
+
+	migrated, err = importcmd.MigrateLighthouseKptfiles(tmpDir)
+	require.NoError(t, err)
+	assert.Empty(t, migrated, "should not migrate v1 Kptfiles")
+}
+
+func TestNewKptfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), importcmd.KptfileName)
+	kf := importcmd.NewKptfile("jenkins-x", "https://github.com/jenkins-x/jx3-pipeline-catalog", "/packs/go/.lighthouse/jenkins-x", "v1.2.3", "abc123")
+	err := importcmd.SaveKptfile(kf, path)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "apiVersion: kpt.dev/v1\n")
+	assert.Contains(t, string(data), "upstreamLock:")
+	assert.Contains(t, string(data), "ref: v1.2.3")
+
+	kf, err = importcmd.LoadKptfile(path)
+	require.NoError(t, err)
+	u := kf.GitUpstream()
+	require.NotNil(t, u)
+	assert.Equal(t, "abc123", u.Commit)
+	assert.Equal(t, "v1.2.3", u.Ref)
+
+	kf.SetGitCommit("def456")
+	assert.Equal(t, "def456", kf.UpstreamLock.Git.Commit)
+	assert.Empty(t, kf.Upstream.Git.Commit)
+}
@@ -0,0 +1,390 @@
+package importcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube/naming"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	// DefaultMonorepoDir the default directory containing the services of a monorepo
+	DefaultMonorepoDir = "services"
+
+	// workspaceSourceDir the directory the source code is cloned into by the pipelines
+	workspaceSourceDir = "/workspace/source"
+)
+
+// MonorepoService a service in a subdirectory of a monorepo
+type MonorepoService struct {
+	// Name the name of the service which is used for the chart and pipeline names
+	Name string
+	// Dir the absolute directory of the service
+	Dir string
+	// Path the path of the service relative to the root of the repository using forward slashes
+	Path string
+	// Pack the pipeline catalog pack used for the service
+	Pack string
+}
+
+// FindMonorepoServices returns the services in the monorepo directory
+func (o *ImportOptions) FindMonorepoServices() ([]*MonorepoService, error) {
+	monorepoDir := o.MonorepoDir
+	if monorepoDir == "" {
+		monorepoDir = DefaultMonorepoDir
+	}
+	dir := filepath.Join(o.Dir, monorepoDir)
+	exists, err := files.DirExists(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check if dir exists %s", dir)
+	}
+	if !exists {
+		return nil, errors.Errorf("the monorepo directory %s does not exist", dir)
+	}
+	fileSlice, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read dir %s", dir)
+	}
+	var answer []*MonorepoService
+	for _, f := range fileSlice {
+		name := f.Name()
+		if !f.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		answer = append(answer, &MonorepoService{
+			Name: naming.ToValidName(strings.ToLower(name)),
+			Dir:  filepath.Join(dir, name),
+			Path: filepath.ToSlash(filepath.Join(monorepoDir, name)),
+		})
+	}
+	sort.Slice(answer, func(i, j int) bool {
+		return answer[i].Path < answer[j].Path
+	})
+	return answer, nil
+}
+
+// EvaluateMonorepoBuildPacks applies a pipeline catalog pack to each service of a monorepo creating a chart for each
+// service and lighthouse triggers which only run when the files in the service change
+func (o *ImportOptions) EvaluateMonorepoBuildPacks(devEnvCloneDir string) error {
+	services, err := o.FindMonorepoServices()
+	if err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		return errors.Errorf("no services found in the monorepo directory %s", filepath.Join(o.Dir, o.MonorepoDir))
+	}
+
+	gitServerName, err := o.getGitServerName()
+	if err != nil {
+		return err
+	}
+	if o.Organisation == "" {
+		o.Organisation, err = o.PickOwner("")
+		if err != nil {
+			return errors.Wrapf(err, "failed to pick a git owner")
+		}
+	}
+	dockerRegistryOrg := o.getDockerRegistryOrg()
+
+	packsDir := ""
+	for _, s := range services {
+		log.Logger().Infof("evaluating the pipeline catalog pack for service %s", termcolor.ColorInfo(s.Path))
+
+		so := o.importOptionsForService(s)
+		if packsDir != "" {
+			// lets reuse the pipeline catalog we picked for the first service
+			so.PipelineCatalogDir = packsDir
+		}
+		i := &InvokeDraftPack{
+			Dir:             s.Dir,
+			DevEnvCloneDir:  devEnvCloneDir,
+			CustomDraftPack: o.Pack,
+			InitialisedGit:  o.InitialisedGit,
+			DisableAddFiles: true,
+			Out:             o.progressOut(),
+		}
+		s.Pack, err = so.InvokeDraftPack(i)
+		if err != nil {
+			return errors.Wrapf(err, "failed to detect the pack for service %s", s.Path)
+		}
+		packsDir = i.PacksDir
+		packDir := filepath.Join(packsDir, s.Pack)
+
+		err = so.copyBuildPack(s.Dir, packDir)
+		if err != nil {
+			return errors.Wrapf(err, "failed to apply the pack %s to service %s", s.Pack, s.Path)
+		}
+		err = so.generateMissingDockerfile(s.Dir, s.Pack)
+		if err != nil {
+			return errors.Wrapf(err, "failed to generate a Dockerfile for service %s", s.Path)
+		}
+		err = removeEmptyDir(filepath.Join(s.Dir, ChartsDir))
+		if err != nil {
+			return err
+		}
+		err = so.RenameChartToMatchAppName()
+		if err != nil {
+			return err
+		}
+		err = so.ReplacePlaceholders(gitServerName, dockerRegistryOrg)
+		if err != nil {
+			return err
+		}
+		err = so.ApplyDeployOptions()
+		if err != nil {
+			return errors.Wrapf(err, "failed to apply the deploy options to the chart of service %s", s.Path)
+		}
+		err = so.VerifyChart()
+		if err != nil {
+			return errors.Wrapf(err, "failed to verify the chart of service %s", s.Path)
+		}
+
+		catalogRef := ""
+		if i.Catalog != nil {
+			catalogRef = i.Catalog.GitRef
+		}
+		triggerDirs, err := o.addMonorepoServiceTriggers(s, filepath.Join(packDir, ".lighthouse"), catalogRef)
+		if err != nil {
+			return errors.Wrapf(err, "failed to add the lighthouse triggers for service %s", s.Path)
+		}
+		for _, dir := range triggerDirs {
+			lo := *so
+			lo.Dir = dir
+			err = lo.ReplacePlaceholders(gitServerName, dockerRegistryOrg)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if o.PostDraftPackCallback != nil {
+		err = o.PostDraftPackCallback()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = o.CreateProwOwnersFile()
+	if err != nil {
+		return err
+	}
+	return o.CreateProwOwnersAliasesFile()
+}
+
+// importOptionsForService creates a copy of the import options to apply a pack to the given service
+func (o *ImportOptions) importOptionsForService(s *MonorepoService) *ImportOptions {
+	so := *o
+	so.Dir = s.Dir
+	so.AppName = s.Name
+	so.PackFilter = func(p *Pack) {
+		if o.PackFilter != nil {
+			o.PackFilter(p)
+		}
+		// the triggers are added to the root of the repository
+		for name, f := range p.Files {
+			if strings.HasPrefix(filepath.ToSlash(name), ".lighthouse/") {
+				_ = f.Close()
+				delete(p.Files, name)
+			}
+		}
+	}
+	return &so
+}
+
+// addMonorepoServiceTriggers copies the lighthouse triggers of the pack into the .lighthouse directory of the
+// repository for the service with a Kptfile so they can be upgraded returning the directories which were added
+func (o *ImportOptions) addMonorepoServiceTriggers(s *MonorepoService, packLighthouseDir, catalogRef string) ([]string, error) {
+	exists, err := files.DirExists(packLighthouseDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check if dir exists %s", packLighthouseDir)
+	}
+	if !exists {
+		return nil, nil
+	}
+	fileSlice, err := os.ReadDir(packLighthouseDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read dir %s", packLighthouseDir)
+	}
+
+	var answer []string
+	for _, f := range fileSlice {
+		if !f.IsDir() {
+			continue
+		}
+		srcDir := filepath.Join(packLighthouseDir, f.Name())
+		triggersFile := filepath.Join(srcDir, "triggers.yaml")
+		exists, err = files.FileExists(triggersFile)
+		if err != nil {
+			return answer, errors.Wrapf(err, "failed to check for triggers in %s", srcDir)
+		}
+		if !exists {
+			continue
+		}
+
+		name := s.Name
+		if f.Name() != "jenkins-x" {
+			name = s.Name + "-" + f.Name()
+		}
+		destDir := filepath.Join(o.Dir, ".lighthouse", name)
+		exists, err = files.DirExists(destDir)
+		if err != nil {
+			return answer, errors.Wrapf(err, "failed to check if dir exists %s", destDir)
+		}
+		if exists {
+			log.Logger().Infof("not adding triggers for service %s as %s already exists", s.Path, destDir)
+			continue
+		}
+		err = files.CopyDirOverwrite(srcDir, destDir)
+		if err != nil {
+			return answer, errors.Wrapf(err, "failed to copy %s to %s", srcDir, destDir)
+		}
+		err = ScopeTriggersToPath(destDir, s.Name, s.Path)
+		if err != nil {
+			return answer, err
+		}
+		hasUses, err := CheckForUsesImage(srcDir, triggersFile)
+		if err != nil {
+			return answer, errors.Wrapf(err, "failed to check for image: uses:sourceURI")
+		}
+		if !hasUses {
+			err = o.saveLighthouseKptfile(packLighthouseDir, s.Pack, f.Name(), destDir, catalogRef)
+			if err != nil {
+				return answer, errors.Wrapf(err, "failed to add the Kptfile for service %s", s.Path)
+			}
+		}
+		log.Logger().Infof("added triggers for service %s to %s", termcolor.ColorInfo(s.Path), termcolor.ColorInfo(destDir))
+		answer = append(answer, destDir)
+	}
+	return answer, nil
+}
+
+// ScopeTriggersToPath modifies the lighthouse triggers and pipelines in the given directory so that they use
+// unique names and only run when files in the given path change and the pipeline steps run in that path
+func ScopeTriggersToPath(dir, name, path string) error {
+	triggersFile := filepath.Join(dir, "triggers.yaml")
+	runIfChanged := fmt.Sprintf("^%s/", regexp.QuoteMeta(strings.TrimSuffix(path, "/")))
+	err := modifyYAMLFile(triggersFile, func(node *yaml.Node) bool {
+		spec := mapValue(node, "spec")
+		modified := false
+		for _, kind := range []string{"presubmits", "postsubmits"} {
+			jobs := mapValue(spec, kind)
+			if jobs == nil || jobs.Kind != yaml.SequenceNode {
+				continue
+			}
+			for _, job := range jobs.Content {
+				if job.Kind != yaml.MappingNode {
+					continue
+				}
+				jobName := ""
+				if v := mapValue(job, "name"); v != nil {
+					jobName = v.Value
+				}
+				if jobName != "" {
+					setMapString(job, "name", name+"-"+jobName)
+				}
+				jobContext := jobName
+				if v := mapValue(job, "context"); v != nil && v.Value != "" {
+					jobContext = v.Value
+				}
+				if jobContext != "" {
+					setMapString(job, "context", name+"-"+jobContext)
+				}
+				// always_run cannot be combined with run_if_changed
+				removeMapKey(job, "always_run")
+				setMapString(job, "run_if_changed", runIfChanged)
+				modified = true
+			}
+		}
+		return modified
+	})
+	if err != nil {
+		return err
+	}
+
+	workingDir := workspaceSourceDir + "/" + strings.TrimSuffix(path, "/")
+	return filepath.Walk(dir, func(f string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() || f == triggersFile || !strings.HasSuffix(f, ".yaml") {
+			return err
+		}
+		return setPipelineRunWorkingDir(f, workingDir)
+	})
+}
+
+// setPipelineRunWorkingDir sets the working directory of the steps of the PipelineRun in the given file
+func setPipelineRunWorkingDir(path, workingDir string) error {
+	return modifyYAMLFile(path, func(node *yaml.Node) bool {
+		kind := mapValue(node, "kind")
+		if kind == nil || kind.Value != "PipelineRun" {
+			return false
+		}
+		tasks := mapValue(mapValue(mapValue(node, "spec"), "pipelineSpec"), "tasks")
+		if tasks == nil || tasks.Kind != yaml.SequenceNode {
+			return false
+		}
+		modified := false
+		for _, task := range tasks.Content {
+			taskSpec := mapValue(task, "taskSpec")
+			if taskSpec == nil || taskSpec.Kind != yaml.MappingNode {
+				continue
+			}
+			stepTemplate := mapValue(taskSpec, "stepTemplate")
+			if stepTemplate == nil {
+				stepTemplate = &yaml.Node{Kind: yaml.MappingNode, Tag: yaml.NodeTagMap}
+				taskSpec.Content = append(taskSpec.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: yaml.NodeTagString, Value: "stepTemplate"}, stepTemplate)
+			}
+			if stepTemplate.Kind != yaml.MappingNode {
+				continue
+			}
+			modified = setMapString(stepTemplate, "workingDir", workingDir) || modified
+		}
+		return modified
+	})
+}
+
+// setMapString sets the string value of the key adding the key if it is missing
+func setMapString(node *yaml.Node, key, value string) bool {
+	if mapValue(node, key) != nil {
+		return setMapValue(node, key, value)
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: yaml.NodeTagString, Value: key}, &yaml.Node{Kind: yaml.ScalarNode, Tag: yaml.NodeTagString, Value: value})
+	return true
+}
+
+// removeMapKey removes the key and its value from the map
+func removeMapKey(node *yaml.Node, key string) bool {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// removeEmptyDir removes the given directory if it exists and is empty
+func removeEmptyDir(dir string) error {
+	exists, err := files.DirExists(dir)
+	if err != nil || !exists {
+		return err
+	}
+	fileList, err := os.ReadDir(dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read dir %s", dir)
+	}
+	if len(fileList) == 0 {
+		err = os.Remove(dir)
+		if err != nil {
+			return errors.Wrapf(err, "failed to remove empty dir %s", dir)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/testimports"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/lighthouse-client/pkg/triggerconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func TestEvaluateMonorepoBuildPacks(t *testing.T) {
+	dir := t.TempDir()
+	err := files.CopyDirOverwrite(filepath.Join("test_data", "monorepo"), dir)
+	require.NoError(t, err, "failed to copy source")
+
+	o := &importcmd.ImportOptions{}
+	_, devEnv, _ := testimports.SetFakeClients(t, o, false)
+	o.DevEnv = devEnv
+	o.Dir = dir
+	o.AppName = "myrepo"
+	o.Organisation = "myorg"
+	o.Pack = "go"
+	o.BatchMode = true
+	o.ScmFactory.GitServerURL = "https://github.com"
+	o.ScmFactory.GitUsername = "myuser"
+	o.PipelineCatalogDir = newTestCatalog(t, filepath.Join("test_data", "plan", "packs"))
+
+	err = o.EvaluateMonorepoBuildPacks(t.TempDir())
+	require.NoError(t, err, "failed to evaluate monorepo packs")
+
+	for _, name := range []string{"cheese", "wine"} {
+		serviceDir := filepath.Join(dir, "services", name)
+		assert.FileExists(t, filepath.Join(serviceDir, "Dockerfile"))
+		assert.FileExists(t, filepath.Join(serviceDir, "charts", name, "Chart.yaml"))
+		assert.NoDirExists(t, filepath.Join(serviceDir, ".lighthouse"), "service should not have its own triggers")
+
+		data, err := os.ReadFile(filepath.Join(serviceDir, "Dockerfile"))
+		require.NoError(t, err, "failed to read Dockerfile")
+		assert.Contains(t, string(data), "/"+name, "Dockerfile should use the service name")
+
+		triggersFile := filepath.Join(dir, ".lighthouse", name, "triggers.yaml")
+		require.FileExists(t, triggersFile)
+		data, err = os.ReadFile(triggersFile)
+		require.NoError(t, err, "failed to read triggers")
+		config := &triggerconfig.Config{}
+		err = yaml.Unmarshal(data, config)
+		require.NoError(t, err, "failed to parse triggers")
+		require.Len(t, config.Spec.Presubmits, 1, "presubmits for %s", name)
+		pr := config.Spec.Presubmits[0]
+		assert.Equal(t, name+"-pr", pr.Name, "presubmit name")
+		assert.Equal(t, name+"-pr", pr.Context, "presubmit context")
+		assert.Equal(t, "^services/"+name+"/", pr.RunIfChanged, "presubmit run_if_changed")
+		assert.False(t, pr.AlwaysRun, "presubmit always_run")
+
+		data, err = os.ReadFile(filepath.Join(dir, ".lighthouse", name, "pullrequest.yaml"))
+		require.NoError(t, err, "failed to read pipeline")
+		assert.Contains(t, string(data), "workingDir: /workspace/source/services/"+name, "pipeline working dir")
+
+		kf, err := importcmd.LoadKptfile(filepath.Join(dir, ".lighthouse", name, importcmd.KptfileName))
+		require.NoError(t, err, "failed to load Kptfile")
+		assert.Equal(t, name, kf.Metadata.Name, "Kptfile name")
+		require.NotNil(t, kf.GitUpstream(), "Kptfile upstream")
+		assert.Equal(t, "https://github.com/jenkins-x/jx3-pipeline-catalog", kf.GitUpstream().Repo, "Kptfile repo")
+		assert.Equal(t, "/packs/go/.lighthouse/jenkins-x", kf.GitUpstream().Directory, "Kptfile directory")
+		assert.NotEmpty(t, kf.GitUpstream().Commit, "Kptfile commit")
+	}
+}
+
+func TestEvaluateMonorepoBuildPacksGoLibrary(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"services/mylib/go.mod": "module example.com/mylib\n\ngo 1.21\n",
+		"services/mylib/lib.go": "package mylib\n\n// Hello returns a greeting\nfunc Hello() string {\n\treturn \"hello\"\n}\n",
+	})
+
+	o := &importcmd.ImportOptions{}
+	_, devEnv, _ := testimports.SetFakeClients(t, o, false)
+	o.DevEnv = devEnv
+	o.Dir = dir
+	o.AppName = "myrepo"
+	o.Organisation = "myorg"
+	// the fake input confirms switching the explicit pack to the release only pack
+	o.Pack = "go"
+	o.ScmFactory.GitServerURL = "https://github.com"
+	o.ScmFactory.GitUsername = "myuser"
+	o.PipelineCatalogDir = newTestCatalog(t, newTestGoLibraryPacks(t))
+
+	err := o.EvaluateMonorepoBuildPacks(t.TempDir())
+	require.NoError(t, err, "failed to evaluate monorepo packs")
+
+	serviceDir := filepath.Join(dir, "services", "mylib")
+	assert.NoFileExists(t, filepath.Join(serviceDir, "Dockerfile"), "library should not have a Dockerfile")
+	assert.NoDirExists(t, filepath.Join(serviceDir, importcmd.ChartsDir), "library should not have a chart")
+	assert.FileExists(t, filepath.Join(dir, ".lighthouse", "mylib", "release.yaml"))
+
+	kf, err := importcmd.LoadKptfile(filepath.Join(dir, ".lighthouse", "mylib", importcmd.KptfileName))
+	require.NoError(t, err, "failed to load Kptfile")
+	require.NotNil(t, kf.GitUpstream(), "Kptfile upstream")
+	assert.Equal(t, "/packs/go-library/.lighthouse/jenkins-x", kf.GitUpstream().Directory, "Kptfile directory")
+}
+
+// newTestCatalog copies the packs into a git repository cloned from the pipeline catalog returning its packs dir
+func newTestCatalog(t *testing.T, packsDir string) string {
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	dir := t.TempDir()
+	answer := filepath.Join(dir, "packs")
+	err := files.CopyDirOverwrite(packsDir, answer)
+	require.NoError(t, err, "failed to copy packs")
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "master"},
+		{"remote", "add", "origin", "https://github.com/jenkins-x/jx3-pipeline-catalog.git"},
+		{"add", "-A"},
+		{"commit", "-q", "-m", "initial"},
+	} {
+		c := exec.Command("git", args...)
+		c.Dir = dir
+		out, err := c.CombinedOutput()
+		require.NoError(t, err, "failed to run git %v: %s", args, string(out))
+	}
+	return answer
+}
+
+func TestScopeTriggersToPath(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"triggers.yaml": `apiVersion: config.lighthouse.jenkins-x.io/v1alpha1
+kind: TriggerConfig
+spec:
+  presubmits:
+  # the pull request pipeline
+  - name: pr
+    context: "pr"
+    always_run: true
+    optional: false
+    source: "pullrequest.yaml"
+`,
+		"pullrequest.yaml": `apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: pullrequest
+spec:
+  pipelineSpec:
+    tasks:
+    - name: from-build-pack
+      taskSpec:
+        steps:
+        # runs the build
+        - name: build
+          image: golang:1.22
+          script: |
+            #!/bin/sh
+            make build
+`,
+	})
+
+	err := importcmd.ScopeTriggersToPath(dir, "cheese", "services/cheese")
+	require.NoError(t, err, "failed to scope triggers")
+
+	assertFileEquals(t, filepath.Join(dir, "triggers.yaml"), `apiVersion: config.lighthouse.jenkins-x.io/v1alpha1
+kind: TriggerConfig
+spec:
+  presubmits:
+  # the pull request pipeline
+  - name: cheese-pr
+    context: "cheese-pr"
+    optional: false
+    source: "pullrequest.yaml"
+    run_if_changed: ^services/cheese/
+`)
+	assertFileEquals(t, filepath.Join(dir, "pullrequest.yaml"), `apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: pullrequest
+spec:
+  pipelineSpec:
+    tasks:
+    - name: from-build-pack
+      taskSpec:
+        steps:
+        # runs the build
+        - name: build
+          image: golang:1.22
+          script: |
+            #!/bin/sh
+            make build
+        stepTemplate:
+          workingDir: /workspace/source/services/cheese
+`)
+}
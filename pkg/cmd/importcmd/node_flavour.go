@@ -0,0 +1,146 @@
+package importcmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/pkg/errors"
+)
+
+const (
+	NODEJS     = "nodejs"
+	JAVASCRIPT = "javascript"
+	TYPESCRIPT = "typescript"
+
+	NPM  = "npm"
+	YARN = "yarn"
+	PNPM = "pnpm"
+)
+
+// PackageJSON the parts of a package.json file used to detect the pack
+type PackageJSON struct {
+	Main            string            `json:"main,omitempty"`
+	Scripts         map[string]string `json:"scripts,omitempty"`
+	Engines         map[string]string `json:"engines,omitempty"`
+	PackageManager  string            `json:"packageManager,omitempty"`
+	Dependencies    map[string]string `json:"dependencies,omitempty"`
+	DevDependencies map[string]string `json:"devDependencies,omitempty"`
+}
+
+// nodeFrameworks the framework of a pack variant and the dependencies which indicate it in order of precedence
+var nodeFrameworks = []struct {
+	name         string
+	dependencies []string
+}{
+	{name: "nextjs", dependencies: []string{"next"}},
+	{name: "nuxtjs", dependencies: []string{"nuxt", "nuxt3"}},
+	{name: "gatsby", dependencies: []string{"gatsby"}},
+	{name: "angular", dependencies: []string{"@angular/core"}},
+	{name: "nestjs", dependencies: []string{"@nestjs/core"}},
+	{name: "react", dependencies: []string{"react-scripts", "react"}},
+	{name: "vue", dependencies: []string{"vue"}},
+	{name: "express", dependencies: []string{"express"}},
+}
+
+// the package managers and their lock files in order of precedence
+var nodeLockFiles = []struct {
+	packageManager string
+	file           string
+}{
+	{packageManager: PNPM, file: "pnpm-lock.yaml"},
+	{packageManager: YARN, file: "yarn.lock"},
+	{packageManager: NPM, file: "package-lock.json"},
+	{packageManager: NPM, file: "npm-shrinkwrap.json"},
+}
+
+var nodeMajorVersion = regexp.MustCompile(`\d+`)
+
+// NodeFlavour returns the pack to use for the package.json in the directory.
+//
+// The first of these packs which exists in the catalog is used: a framework variant like nodejs-nextjs, a variant for the
+// major version of engines.node and the package manager like nodejs20-pnpm, the node version like nodejs20 or the
+// package manager like nodejs-yarn. Otherwise typescript is used if it is a dependency and the catalog has the pack
+// or javascript
+func NodeFlavour(packsDir, dir string) (string, error) {
+	path := filepath.Join(dir, "package.json")
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read file %s", path)
+	}
+	p := &PackageJSON{}
+	err = json.Unmarshal(b, p)
+	if err != nil {
+		// lets not fail the import due to an invalid package.json
+		return JAVASCRIPT, nil
+	}
+
+	packageManager, err := NodePackageManager(dir, p)
+	if err != nil {
+		return "", err
+	}
+	version := nodeMajorVersion.FindString(p.Engines["node"])
+
+	var packs []string
+	for _, framework := range nodeFrameworks {
+		if p.hasDependency(framework.dependencies...) {
+			packs = append(packs, NODEJS+"-"+framework.name)
+		}
+	}
+	if version != "" {
+		if packageManager != NPM {
+			packs = append(packs, NODEJS+version+"-"+packageManager)
+		}
+		packs = append(packs, NODEJS+version)
+	}
+	if packageManager != NPM {
+		packs = append(packs, NODEJS+"-"+packageManager)
+	}
+	if p.hasDependency(TYPESCRIPT) {
+		packs = append(packs, TYPESCRIPT)
+	}
+	for _, pack := range packs {
+		if exists, _ := files.DirExists(filepath.Join(packsDir, pack)); exists {
+			return pack, nil
+		}
+	}
+	return JAVASCRIPT, nil
+}
+
+// NodePackageManager returns the package manager from the packageManager field of the package.json or the lock file
+// in the directory defaulting to npm
+func NodePackageManager(dir string, p *PackageJSON) (string, error) {
+	if p != nil && p.PackageManager != "" {
+		name := strings.Split(p.PackageManager, "@")[0]
+		switch name {
+		case NPM, YARN, PNPM:
+			return name, nil
+		}
+	}
+	for _, l := range nodeLockFiles {
+		path := filepath.Join(dir, l.file)
+		exists, err := files.FileExists(path)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to check if file exists %s", path)
+		}
+		if exists {
+			return l.packageManager, nil
+		}
+	}
+	return NPM, nil
+}
+
+func (p *PackageJSON) hasDependency(names ...string) bool {
+	for _, name := range names {
+		if _, ok := p.Dependencies[name]; ok {
+			return true
+		}
+		if _, ok := p.DevDependencies[name]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeFlavour(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		packs    []string
+		expected string
+	}{
+		{
+			name:     "default",
+			files:    map[string]string{"package.json": `{"name": "myapp"}`},
+			packs:    []string{"nodejs20"},
+			expected: importcmd.JAVASCRIPT,
+		},
+		{
+			name:     "invalid package.json",
+			files:    map[string]string{"package.json": `{"name": `},
+			expected: importcmd.JAVASCRIPT,
+		},
+		{
+			name:     "nextjs",
+			files:    map[string]string{"package.json": `{"engines": {"node": ">=20"}, "dependencies": {"next": "14.0.0", "react": "18.2.0"}}`},
+			packs:    []string{"nodejs-nextjs", "nodejs-react", "nodejs20"},
+			expected: "nodejs-nextjs",
+		},
+		{
+			name:     "framework missing from catalog",
+			files:    map[string]string{"package.json": `{"engines": {"node": "^18.17.0"}, "dependencies": {"express": "4.18.2"}}`},
+			packs:    []string{"nodejs18", "nodejs20"},
+			expected: "nodejs18",
+		},
+		{
+			name: "pnpm lock file",
+			files: map[string]string{
+				"package.json":   `{"engines": {"node": "20.x"}}`,
+				"pnpm-lock.yaml": "lockfileVersion: '6.0'",
+			},
+			packs:    []string{"nodejs20", "nodejs20-pnpm"},
+			expected: "nodejs20-pnpm",
+		},
+		{
+			name: "yarn lock file",
+			files: map[string]string{
+				"package.json": `{}`,
+				"yarn.lock":    "",
+			},
+			packs:    []string{"nodejs-yarn"},
+			expected: "nodejs-yarn",
+		},
+		{
+			name:     "package manager field",
+			files:    map[string]string{"package.json": `{"packageManager": "yarn@4.0.2"}`},
+			packs:    []string{"nodejs-yarn"},
+			expected: "nodejs-yarn",
+		},
+		{
+			name:     "typescript",
+			files:    map[string]string{"package.json": `{"devDependencies": {"typescript": "5.2.2"}}`},
+			packs:    []string{importcmd.TYPESCRIPT},
+			expected: importcmd.TYPESCRIPT,
+		},
+		{
+			name:     "typescript missing from catalog",
+			files:    map[string]string{"package.json": `{"devDependencies": {"typescript": "5.2.2"}}`},
+			expected: importcmd.JAVASCRIPT,
+		},
+	}
+	for _, tc := range testCases {
+		dir := t.TempDir()
+		for name, content := range tc.files {
+			err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600)
+			require.NoError(t, err, "failed to write %s for %s", name, tc.name)
+		}
+		packsDir := t.TempDir()
+		for _, pack := range tc.packs {
+			err := os.Mkdir(filepath.Join(packsDir, pack), 0700)
+			require.NoError(t, err, "failed to create pack %s for %s", pack, tc.name)
+		}
+
+		flavour, err := importcmd.NodeFlavour(packsDir, dir)
+		require.NoError(t, err, "failed to detect flavour for %s", tc.name)
+		assert.Equal(t, tc.expected, flavour, "flavour for %s", tc.name)
+	}
+}
@@ -87,6 +87,13 @@ func (p *Pack) SaveDir(dest, packName string) error {
 	return nil
 }
 
+// Close closes any of the pack files which have not been saved
+func (p *Pack) Close() {
+	for _, f := range p.Files {
+		_ = f.Close()
+	}
+}
+
 func saveFile(path string, f io.ReadCloser) error {
 	// let's make sure the parent dir exists
 	parent := filepath.Dir(path)
@@ -204,6 +211,10 @@ func loadDirectory(pack *Pack, dir, relPath string) error {
 	}
 	for _, fInfo := range fileSlice {
 		name := fInfo.Name()
+		if relPath == "" && name == DetectFileName {
+			// the pack detection rules are not part of the project
+			continue
+		}
 		chartPath := filepath.Join(dir, name)
 		if fInfo.IsDir() {
 			// assume root folders not starting with dot are chart folders
@@ -0,0 +1,162 @@
+package importcmd
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/constants"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/options"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/yamls"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// PlaceholdersFileName the name of the file in the extensions directory of the dev environment git repository which
+// defines extra placeholders
+const PlaceholdersFileName = "placeholders.yaml"
+
+// defaultGitBranch the branch used if the default branch of the repository cannot be found
+const defaultGitBranch = "master"
+
+// Placeholders the extra placeholders defined in the extensions directory of the dev environment git repository
+type Placeholders struct {
+	// Placeholders the placeholder values indexed by name. The REPLACE_ME_ prefix is added to names without it
+	Placeholders map[string]string `json:"placeholders,omitempty"`
+}
+
+var javaKeywords = map[string]bool{
+	"abstract": true, "assert": true, "boolean": true, "break": true, "byte": true, "case": true, "catch": true,
+	"char": true, "class": true, "const": true, "continue": true, "default": true, "do": true, "double": true,
+	"else": true, "enum": true, "extends": true, "final": true, "finally": true, "float": true, "for": true,
+	"goto": true, "if": true, "implements": true, "import": true, "instanceof": true, "int": true,
+	"interface": true, "long": true, "native": true, "new": true, "package": true, "private": true,
+	"protected": true, "public": true, "return": true, "short": true, "static": true, "strictfp": true,
+	"super": true, "switch": true, "synchronized": true, "this": true, "throw": true, "throws": true,
+	"transient": true, "try": true, "void": true, "volatile": true, "while": true, "true": true, "false": true,
+	"null": true,
+}
+
+// PlaceholderName returns the name of the placeholder for the key adding the REPLACE_ME_ prefix if required
+func PlaceholderName(key string) string {
+	key = strings.ToUpper(strings.TrimSpace(key))
+	if strings.HasPrefix(key, constants.PlaceHolderPrefix) {
+		return key
+	}
+	return constants.PlaceHolderPrefix + "_" + key
+}
+
+// JavaPackageName returns a valid java package name for the names such as the org and app name
+func JavaPackageName(names ...string) string {
+	var parts []string
+	for _, name := range names {
+		for _, segment := range strings.Split(name, ".") {
+			buf := strings.Builder{}
+			for _, r := range strings.ToLower(segment) {
+				switch {
+				case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+					buf.WriteRune(r)
+				case r == '-' || r == '_' || r == ' ':
+					buf.WriteRune('_')
+				}
+			}
+			part := strings.Trim(buf.String(), "_")
+			if part == "" {
+				continue
+			}
+			if unicode.IsDigit(rune(part[0])) || javaKeywords[part] {
+				part = "_" + part
+			}
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// parsePlaceholderArgs parses the KEY=VALUE arguments of --set-placeholder
+func parsePlaceholderArgs(args []string) (map[string]string, error) {
+	answer := map[string]string{}
+	for _, arg := range args {
+		i := strings.Index(arg, "=")
+		if i <= 0 {
+			return nil, options.InvalidOptionf("set-placeholder", arg, "should be of the form KEY=VALUE")
+		}
+		answer[PlaceholderName(arg[:i])] = arg[i+1:]
+	}
+	return answer, nil
+}
+
+// loadDevPlaceholders loads the extra placeholders from the extensions directory of the dev environment git clone
+func (o *ImportOptions) loadDevPlaceholders(devEnvCloneDir string) error {
+	path := filepath.Join(devEnvCloneDir, "extensions", PlaceholdersFileName)
+	exists, err := files.FileExists(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if file exists %s", path)
+	}
+	if !exists {
+		return nil
+	}
+	placeholders := &Placeholders{}
+	err = yamls.LoadFile(path, placeholders)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load placeholders file %s", path)
+	}
+	o.devPlaceholders = map[string]string{}
+	for k, v := range placeholders.Placeholders {
+		o.devPlaceholders[PlaceholderName(k)] = v
+	}
+	return nil
+}
+
+// userPlaceholders returns the placeholders defined in the dev environment, the project configuration and via
+// --set-placeholder in increasing order of precedence
+func (o *ImportOptions) userPlaceholders() map[string]string {
+	answer := map[string]string{}
+	for k, v := range o.devPlaceholders {
+		answer[k] = v
+	}
+	if o.projectConfig != nil {
+		for k, v := range o.projectConfig.Placeholders {
+			answer[PlaceholderName(k)] = v
+		}
+	}
+	values, err := parsePlaceholderArgs(o.SetPlaceholders)
+	if err != nil {
+		log.Logger().Warnf("ignoring invalid placeholders: %s", err.Error())
+	}
+	for k, v := range values {
+		answer[k] = v
+	}
+	return answer
+}
+
+// repositoryName returns the name of the git repository
+func (o *ImportOptions) repositoryName() string {
+	if o.gitInfo != nil && o.gitInfo.Name != "" {
+		return o.gitInfo.Name
+	}
+	name := o.GitRepositoryOptions.Name
+	if name == "" {
+		name = o.Repository
+	}
+	if name == "" {
+		name = o.AppName
+	}
+	return name
+}
+
+// defaultBranch returns the current branch of the git repository or the git default branch
+func (o *ImportOptions) defaultBranch() string {
+	if o.Dir != "" {
+		exists, err := files.DirExists(filepath.Join(o.Dir, ".git"))
+		if err == nil && exists {
+			branch, err := gitclient.Branch(o.Git(), o.Dir)
+			if err == nil && branch != "" && branch != "HEAD" {
+				return branch
+			}
+		}
+	}
+	return defaultGitBranch
+}
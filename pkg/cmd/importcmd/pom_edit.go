@@ -0,0 +1,292 @@
+package importcmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// mavenPluginsGroupID the default group of maven plugins
+const mavenPluginsGroupID = "org.apache.maven.plugins"
+
+var versionNumbers = regexp.MustCompile(`\d+`)
+
+// pomTextRange the byte range of some text in a pom.xml
+type pomTextRange struct {
+	start int
+	end   int
+	value string
+}
+
+// pomPluginElement the location of a plugin element in a pom.xml
+type pomPluginElement struct {
+	groupID    string
+	artifactID string
+	version    *pomTextRange
+	managed    bool
+	// end the offset of the plugin end element
+	end int
+}
+
+// pomLayout the locations of the plugins and properties in a pom.xml which can be edited
+type pomLayout struct {
+	plugins    []*pomPluginElement
+	properties map[string]*pomTextRange
+	// pluginsEnd, buildEnd and projectEnd the offsets of the end elements or -1 if missing
+	pluginsEnd int
+	buildEnd   int
+	projectEnd int
+	// indent the indentation of the child elements of the project
+	indent string
+}
+
+// UpdatePomPluginVersion ensures the maven plugin in the pom.xml data has at least the given version adding the
+// plugin to the build if it is missing. Only the version text is changed so the rest of the formatting is kept
+func UpdatePomPluginVersion(data []byte, artifactID, minVersion string) ([]byte, bool, error) {
+	layout, err := parsePomLayout(data)
+	if err != nil {
+		return data, false, err
+	}
+
+	var edits []pomTextRange
+	found := false
+	for _, p := range layout.plugins {
+		if p.artifactID != artifactID || (p.groupID != "" && p.groupID != mavenPluginsGroupID) {
+			continue
+		}
+		found = true
+		if p.version == nil {
+			if !p.managed && !layout.hasManagedVersion(artifactID) {
+				edits = append(edits, insertPomElement(data, p.end, layout.indent+"<version>"+minVersion+"</version>"))
+			}
+			continue
+		}
+		r := p.version
+		if strings.HasPrefix(r.value, "${") && strings.HasSuffix(r.value, "}") {
+			// lets update the property if its defined in this pom
+			r = layout.properties[r.value[2:len(r.value)-1]]
+			if r == nil {
+				continue
+			}
+		}
+		if CompareVersions(r.value, minVersion) < 0 {
+			edits = append(edits, pomTextRange{start: r.start, end: r.end, value: minVersion})
+		}
+	}
+	if !found {
+		edits = append(edits, layout.addPlugin(data, artifactID, minVersion))
+	}
+	if len(edits) == 0 {
+		return data, false, nil
+	}
+	return applyEdits(data, edits), true, nil
+}
+
+// UpdatePomPluginVersions updates the pom.xml file so that each plugin artifact id has at least the given version
+func UpdatePomPluginVersions(path string, minVersions [][2]string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to read %s", path)
+	}
+	modified := false
+	for _, v := range minVersions {
+		var changed bool
+		data, changed, err = UpdatePomPluginVersion(data, v[0], v[1])
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to update plugin %s in %s", v[0], path)
+		}
+		modified = modified || changed
+	}
+	if !modified {
+		return false, nil
+	}
+	err = os.WriteFile(path, data, 0o600)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to save %s", path)
+	}
+	return true, nil
+}
+
+// CompareVersions compares the numbers in the versions returning a negative number if a is older than b, zero if they
+// are the same and a positive number if a is newer than b
+func CompareVersions(a, b string) int {
+	an := versionNumbers.FindAllString(a, -1)
+	bn := versionNumbers.FindAllString(b, -1)
+	for i := 0; i < len(an) || i < len(bn); i++ {
+		x, y := 0, 0
+		if i < len(an) {
+			x, _ = strconv.Atoi(an[i])
+		}
+		if i < len(bn) {
+			y, _ = strconv.Atoi(bn[i])
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
+
+func parsePomLayout(data []byte) (*pomLayout, error) {
+	layout := &pomLayout{
+		properties: map[string]*pomTextRange{},
+		pluginsEnd: -1,
+		buildEnd:   -1,
+		projectEnd: -1,
+	}
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var path []string
+	var plugin *pomPluginElement
+	for {
+		offset := int(d.InputOffset())
+		t, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse the pom.xml")
+		}
+		switch e := t.(type) {
+		case xml.StartElement:
+			path = append(path, e.Name.Local)
+			if layout.indent == "" && len(path) == 2 {
+				layout.indent = indentOf(data, offset)
+			}
+			switch strings.Join(path, "/") {
+			case "project/build/plugins/plugin":
+				plugin = &pomPluginElement{}
+			case "project/build/pluginManagement/plugins/plugin":
+				plugin = &pomPluginElement{managed: true}
+			}
+		case xml.CharData:
+			if len(path) == 3 && path[1] == "properties" {
+				layout.properties[path[2]] = newPomTextRange(data, offset, int(d.InputOffset()))
+			}
+			if plugin != nil && path[len(path)-2] == "plugin" {
+				r := newPomTextRange(data, offset, int(d.InputOffset()))
+				switch path[len(path)-1] {
+				case "groupId":
+					plugin.groupID = r.value
+				case "artifactId":
+					plugin.artifactID = r.value
+				case "version":
+					plugin.version = r
+				}
+			}
+		case xml.EndElement:
+			switch strings.Join(path, "/") {
+			case "project/build/plugins/plugin", "project/build/pluginManagement/plugins/plugin":
+				if plugin != nil {
+					plugin.end = offset
+					layout.plugins = append(layout.plugins, plugin)
+					plugin = nil
+				}
+			case "project/build/plugins":
+				layout.pluginsEnd = offset
+			case "project/build":
+				layout.buildEnd = offset
+			case "project":
+				layout.projectEnd = offset
+			}
+			if len(path) > 0 {
+				path = path[:len(path)-1]
+			}
+		}
+	}
+	if layout.projectEnd < 0 {
+		return nil, ErrNotPom
+	}
+	if layout.indent == "" {
+		layout.indent = "  "
+	}
+	return layout, nil
+}
+
+func (l *pomLayout) hasManagedVersion(artifactID string) bool {
+	for _, p := range l.plugins {
+		if p.managed && p.artifactID == artifactID && p.version != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// addPlugin returns the edit to add the plugin to the build plugins creating the plugins and build elements if required
+func (l *pomLayout) addPlugin(data []byte, artifactID, version string) pomTextRange {
+	lines := []string{
+		"<plugin>",
+		l.indent + "<groupId>" + mavenPluginsGroupID + "</groupId>",
+		l.indent + "<artifactId>" + artifactID + "</artifactId>",
+		l.indent + "<version>" + version + "</version>",
+		"</plugin>",
+	}
+	wrap := func(name string) {
+		for i := range lines {
+			lines[i] = l.indent + lines[i]
+		}
+		lines = append(append([]string{"<" + name + ">"}, lines...), "</"+name+">")
+	}
+
+	offset := l.pluginsEnd
+	if offset < 0 {
+		wrap("plugins")
+		offset = l.buildEnd
+		if offset < 0 {
+			wrap("build")
+			offset = l.projectEnd
+		}
+	}
+	return insertPomElement(data, offset, l.indent+strings.Join(lines, "\n"+l.indent))
+}
+
+func newPomTextRange(data []byte, start, end int) *pomTextRange {
+	text := string(data[start:end])
+	trimmed := strings.TrimLeft(text, " \t\r\n")
+	start += len(text) - len(trimmed)
+	value := strings.TrimRight(trimmed, " \t\r\n")
+	return &pomTextRange{start: start, end: start + len(value), value: value}
+}
+
+// applyEdits replaces the ranges of the data with the edit values. The edits must not overlap
+func applyEdits(data []byte, edits []pomTextRange) []byte {
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].start < edits[j].start
+	})
+	var buf bytes.Buffer
+	last := 0
+	for _, e := range edits {
+		buf.Write(data[last:e.start])
+		buf.WriteString(e.value)
+		last = e.end
+	}
+	buf.Write(data[last:])
+	return buf.Bytes()
+}
+
+// insertPomElement returns the edit to insert the text on its own line before the end element at the offset
+func insertPomElement(data []byte, offset int, text string) pomTextRange {
+	start := lineStart(data, offset)
+	if strings.TrimSpace(string(data[start:offset])) != "" {
+		return pomTextRange{start: offset, end: offset, value: text}
+	}
+	indent := indentOf(data, offset)
+	return pomTextRange{start: start, end: start, value: indent + strings.ReplaceAll(text, "\n", "\n"+indent) + "\n"}
+}
+
+// lineStart returns the offset of the start of the line containing the offset
+func lineStart(data []byte, offset int) int {
+	return bytes.LastIndexByte(data[:offset], '\n') + 1
+}
+
+// indentOf returns the whitespace at the start of the line containing the offset
+func indentOf(data []byte, offset int) string {
+	line := string(data[lineStart(data, offset):offset])
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
@@ -0,0 +1,279 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdatePomPluginVersion(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pom      string
+		expected string
+	}{
+		{
+			name: "old-version",
+			pom: `<project>
+    <build>
+        <plugins>
+            <plugin>
+                <!-- deploys the jar -->
+                <artifactId>maven-deploy-plugin</artifactId>
+                <version>2.8.2</version>
+            </plugin>
+        </plugins>
+    </build>
+</project>
+`,
+			expected: `<project>
+    <build>
+        <plugins>
+            <plugin>
+                <!-- deploys the jar -->
+                <artifactId>maven-deploy-plugin</artifactId>
+                <version>3.1.4</version>
+            </plugin>
+        </plugins>
+    </build>
+</project>
+`,
+		},
+		{
+			name: "newer-version",
+			pom: `<project>
+  <build><plugins><plugin><artifactId>maven-deploy-plugin</artifactId><version>3.2.0</version></plugin></plugins></build>
+</project>
+`,
+		},
+		{
+			name: "property",
+			pom: `<project>
+  <properties>
+    <deploy.version>3.0.0-M1</deploy.version>
+  </properties>
+  <build>
+    <pluginManagement>
+      <plugins>
+        <plugin>
+          <groupId>org.apache.maven.plugins</groupId>
+          <artifactId>maven-deploy-plugin</artifactId>
+          <version>${deploy.version}</version>
+        </plugin>
+      </plugins>
+    </pluginManagement>
+    <plugins>
+      <plugin>
+        <artifactId>maven-deploy-plugin</artifactId>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`,
+			expected: `<project>
+  <properties>
+    <deploy.version>3.1.4</deploy.version>
+  </properties>
+  <build>
+    <pluginManagement>
+      <plugins>
+        <plugin>
+          <groupId>org.apache.maven.plugins</groupId>
+          <artifactId>maven-deploy-plugin</artifactId>
+          <version>${deploy.version}</version>
+        </plugin>
+      </plugins>
+    </pluginManagement>
+    <plugins>
+      <plugin>
+        <artifactId>maven-deploy-plugin</artifactId>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`,
+		},
+		{
+			name: "missing-version",
+			pom: `<project>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-deploy-plugin</artifactId>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`,
+			expected: `<project>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-deploy-plugin</artifactId>
+        <version>3.1.4</version>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`,
+		},
+		{
+			name: "missing-plugin",
+			pom: `<project>
+	<build>
+		<plugins>
+			<plugin>
+				<groupId>org.springframework.boot</groupId>
+				<artifactId>spring-boot-maven-plugin</artifactId>
+			</plugin>
+		</plugins>
+	</build>
+</project>
+`,
+			expected: `<project>
+	<build>
+		<plugins>
+			<plugin>
+				<groupId>org.springframework.boot</groupId>
+				<artifactId>spring-boot-maven-plugin</artifactId>
+			</plugin>
+			<plugin>
+				<groupId>org.apache.maven.plugins</groupId>
+				<artifactId>maven-deploy-plugin</artifactId>
+				<version>3.1.4</version>
+			</plugin>
+		</plugins>
+	</build>
+</project>
+`,
+		},
+		{
+			name: "missing-build",
+			pom: `<project>
+  <modelVersion>4.0.0</modelVersion>
+</project>
+`,
+			expected: `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <build>
+    <plugins>
+      <plugin>
+        <groupId>org.apache.maven.plugins</groupId>
+        <artifactId>maven-deploy-plugin</artifactId>
+        <version>3.1.4</version>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`,
+		},
+		{
+			name: "other-group",
+			pom: `<project>
+  <build>
+    <plugins>
+      <plugin>
+        <groupId>com.example</groupId>
+        <artifactId>maven-deploy-plugin</artifactId>
+        <version>1.0</version>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`,
+			expected: `<project>
+  <build>
+    <plugins>
+      <plugin>
+        <groupId>com.example</groupId>
+        <artifactId>maven-deploy-plugin</artifactId>
+        <version>1.0</version>
+      </plugin>
+      <plugin>
+        <groupId>org.apache.maven.plugins</groupId>
+        <artifactId>maven-deploy-plugin</artifactId>
+        <version>3.1.4</version>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, modified, err := importcmd.UpdatePomPluginVersion([]byte(tc.pom), "maven-deploy-plugin", "3.1.4")
+			require.NoError(t, err, "failed to update the pom")
+			if tc.expected == "" {
+				assert.False(t, modified, "should not have modified the pom")
+				assert.Equal(t, tc.pom, string(data))
+				return
+			}
+			assert.True(t, modified, "should have modified the pom")
+			assert.Equal(t, tc.expected, string(data))
+		})
+	}
+
+	_, _, err := importcmd.UpdatePomPluginVersion([]byte("<project>"), "maven-deploy-plugin", "3.1.4")
+	assert.Error(t, err, "should fail to parse an invalid pom")
+}
+
+func TestUpdatePomPluginVersions(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"pom.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <build>
+    <plugins>
+      <plugin>
+        <groupId>org.apache.maven.plugins</groupId>
+        <artifactId>maven-surefire-plugin</artifactId>
+        <version>2.22.2</version>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`,
+	})
+
+	path := filepath.Join(dir, "pom.xml")
+	modified, err := importcmd.UpdatePomPluginVersions(path, [][2]string{
+		{"maven-deploy-plugin", "3.1.4"},
+		{"maven-surefire-plugin", "3.5.4"},
+	})
+	require.NoError(t, err, "failed to update the pom")
+	assert.True(t, modified, "should have modified the pom")
+	assertFileEquals(t, path, `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <build>
+    <plugins>
+      <plugin>
+        <groupId>org.apache.maven.plugins</groupId>
+        <artifactId>maven-surefire-plugin</artifactId>
+        <version>3.5.4</version>
+      </plugin>
+      <plugin>
+        <groupId>org.apache.maven.plugins</groupId>
+        <artifactId>maven-deploy-plugin</artifactId>
+        <version>3.1.4</version>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`)
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Less(t, importcmd.CompareVersions("2.8.2", "3.1.4"), 0)
+	assert.Less(t, importcmd.CompareVersions("3.0.0-M5", "3.1.4"), 0)
+	assert.Less(t, importcmd.CompareVersions("3.1", "3.1.4"), 0)
+	assert.Equal(t, 0, importcmd.CompareVersions("3.1.4", "3.1.4"))
+	assert.Greater(t, importcmd.CompareVersions("3.10.0", "3.5.4"), 0)
+}
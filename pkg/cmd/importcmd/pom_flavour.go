@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
 )
 
 const (
@@ -17,48 +18,178 @@ const (
 	DROPWIZARD = "dropwizard"
 )
 
+// PomFramework a framework detected in a maven project
+type PomFramework struct {
+	// Name the name of the framework which is used for the pack name
+	Name string
+	// Version the resolved version of the framework if known
+	Version string
+}
+
+// MajorVersion returns the major version of the framework
+func (f *PomFramework) MajorVersion() string {
+	return strings.Split(f.Version, ".")[0]
+}
+
+// PomFlavour returns the maven pack to use for the pom.xml.
+//
+// The pom.xml is loaded with its local parents and modules. Liberty, Dropwizard and Tomcat projects use their packs,
+// then Quarkus, Micronaut and Spring Boot packs are used if the catalog has them then a maven-javaNN pack for the java
+// version, otherwise the maven pack
 func PomFlavour(packsDir, pomPath string) (string, error) {
+	m, err := LoadPom(pomPath)
+	if err != nil {
+		if err != ErrNotPom {
+			log.Logger().Debugf("failed to load maven project %s: %s", pomPath, err.Error())
+		}
+		return pomSnippetFlavour(packsDir, pomPath), nil
+	}
+	reactor := m.Reactor()
+	for _, r := range reactor {
+		if r.Packaging() == "war" && r.FindArtifact("org.eclipse.microprofile", "") != nil {
+			return LIBERTY, nil
+		}
+	}
+	for _, r := range reactor {
+		if r.FindArtifact("io.dropwizard", "") != nil {
+			return DROPWIZARD, nil
+		}
+	}
+	for _, r := range reactor {
+		if r.FindArtifact("org.apache.tomcat", "") != nil {
+			return APPSERVER, nil
+		}
+	}
+
+	packExists := func(pack string) bool {
+		exists, _ := files.DirExists(filepath.Join(packsDir, pack))
+		return exists
+	}
+	for _, f := range m.Frameworks() {
+		var packs []string
+		if major := f.MajorVersion(); major != "" {
+			packs = append(packs, MAVEN+"-"+f.Name+major, f.Name+major)
+		}
+		packs = append(packs, MAVEN+"-"+f.Name, f.Name)
+		for _, pack := range packs {
+			if packExists(pack) {
+				return pack, nil
+			}
+		}
+	}
+
+	for _, r := range reactor {
+		version := r.JavaVersion()
+		if version != "" {
+			pack := "maven-java" + version
+			if packExists(pack) {
+				return pack, nil
+			}
+			break
+		}
+	}
+	return MAVEN, nil
+}
+
+// Frameworks returns the Quarkus, Micronaut and Spring Boot frameworks used by any project of the reactor
+func (m *PomModel) Frameworks() []*PomFramework {
+	detectors := []struct {
+		name      string
+		artifacts [][2]string
+	}{
+		{
+			name: QUARKUS,
+			artifacts: [][2]string{
+				{"io.quarkus.platform", "quarkus-bom"},
+				{"io.quarkus", "quarkus-bom"},
+				{"io.quarkus.platform", "quarkus-maven-plugin"},
+				{"io.quarkus", "quarkus-maven-plugin"},
+				{"io.quarkus", ""},
+			},
+		},
+		{
+			name: MICRONAUT,
+			artifacts: [][2]string{
+				{"io.micronaut.platform", "micronaut-parent"},
+				{"io.micronaut", "micronaut-parent"},
+				{"io.micronaut.platform", "micronaut-platform"},
+				{"io.micronaut", "micronaut-bom"},
+				{"io.micronaut", ""},
+			},
+		},
+		{
+			name: SPRINGBOOT,
+			artifacts: [][2]string{
+				{"org.springframework.boot", "spring-boot-starter-parent"},
+				{"org.springframework.boot", "spring-boot-dependencies"},
+				{"org.springframework.boot", "spring-boot-maven-plugin"},
+				{"org.springframework.boot", ""},
+			},
+		},
+	}
+
+	var answer []*PomFramework
+	for _, d := range detectors {
+		var found *PomFramework
+		for _, r := range m.Reactor() {
+			for _, a := range d.artifacts {
+				artifact := r.FindArtifact(a[0], a[1])
+				if artifact == nil {
+					continue
+				}
+				if found == nil {
+					found = &PomFramework{Name: d.name}
+				}
+				if found.Version == "" && !strings.Contains(artifact.Version, "${") {
+					found.Version = artifact.Version
+				}
+			}
+		}
+		if found != nil {
+			answer = append(answer, found)
+		}
+	}
+	return answer
+}
+
+// pomSnippetFlavour detects the flavour of a file which is not a well formed maven project
+func pomSnippetFlavour(packsDir, pomPath string) string {
 	b, err := os.ReadFile(pomPath)
 	if err != nil {
-		return "", nil
+		return MAVEN
 	}
 
 	s := string(b)
 	if strings.Contains(s, "<packaging>war</packaging>") &&
 		strings.Contains(s, "org.eclipse.microprofile") {
-		return LIBERTY, nil
+		return LIBERTY
 	}
 	if strings.Contains(s, "<groupId>io.dropwizard") {
-		return DROPWIZARD, nil
+		return DROPWIZARD
 	}
 	if strings.Contains(s, "<groupId>org.apache.tomcat") {
-		return APPSERVER, nil
-	}
-	// java.version is used by Spring Boot
-	version, ok := getProp(s, "java.version")
-	if !ok {
-		// maven-compiler-plugin 3.6 and later versions supports and recommends maven.compiler.release
-		version, ok = getProp(s, "maven.compiler.release")
+		return APPSERVER
 	}
-	if !ok {
-		// older versions of maven-compiler-plugin uses maven.compiler.target
-		version, ok = getProp(s, "maven.compiler.target")
-	}
-	if ok {
+	for _, name := range []string{"java.version", "maven.compiler.release", "maven.compiler.target"} {
+		version, ok := getProp(s, name)
+		if !ok {
+			continue
+		}
 		pack := "maven-java" + version
 		if exists, _ := files.DirExists(filepath.Join(packsDir, pack)); exists {
-			return pack, nil
+			return pack
 		}
+		break
 	}
-
-	return MAVEN, nil
+	return MAVEN
 }
 
 func getProp(pom, prop string) (string, bool) {
-	propPattern := regexp.MustCompile(fmt.Sprintf("<%s>(\\d+)</%s>", prop, prop))
+	propPattern := regexp.MustCompile(fmt.Sprintf("<%s>\\s*([\\d._]+)\\s*</%s>", regexp.QuoteMeta(prop), regexp.QuoteMeta(prop)))
 	matches := propPattern.FindStringSubmatch(pom)
 	if matches != nil {
-		return matches[1], true
+		version := normaliseJavaVersion(matches[1])
+		return version, version != ""
 	}
 	return "", false
 }
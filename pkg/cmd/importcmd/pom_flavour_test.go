@@ -10,6 +10,7 @@ import (
 
 	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMavenIsDefault(t *testing.T) {
@@ -67,3 +68,87 @@ func genericMaven(t *testing.T, packsDir, expectedFlavour, testFileContent strin
 	err = os.Remove(file.Name())
 	assert.Nil(t, err)
 }
+
+func TestPomFlavourModel(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		packs    []string
+		expected string
+	}{
+		{
+			name:     "java8",
+			packs:    []string{"maven-java8"},
+			expected: "maven-java8",
+		},
+		{
+			name:     "reactor",
+			expected: importcmd.DROPWIZARD,
+		},
+		{
+			name:     "springboot",
+			packs:    []string{"maven-spring-boot3", "maven-spring-boot", "maven-java17"},
+			expected: "maven-spring-boot3",
+		},
+		{
+			name:     "springboot",
+			packs:    []string{"maven-java17"},
+			expected: "maven-java17",
+		},
+		{
+			name:     "quarkus",
+			packs:    []string{importcmd.QUARKUS, "maven-java17"},
+			expected: importcmd.QUARKUS,
+		},
+		{
+			name:     "micronaut",
+			packs:    []string{"maven-micronaut"},
+			expected: "maven-micronaut",
+		},
+		{
+			name:     filepath.Join("parent_property", "child"),
+			packs:    []string{"maven-java21"},
+			expected: "maven-java21",
+		},
+	}
+	for _, tc := range testCases {
+		packsDir := t.TempDir()
+		for _, pack := range tc.packs {
+			err := os.Mkdir(filepath.Join(packsDir, pack), 0700)
+			require.NoError(t, err, "failed to create pack %s for %s", pack, tc.name)
+		}
+
+		flavour, err := importcmd.PomFlavour(packsDir, filepath.Join("test_data", "pom_flavour", tc.name, "pom.xml"))
+		require.NoError(t, err, "failed to detect flavour for %s", tc.name)
+		assert.Equal(t, tc.expected, flavour, "flavour for %s with packs %v", tc.name, tc.packs)
+	}
+}
+
+func TestLoadPom(t *testing.T) {
+	t.Parallel()
+
+	m, err := importcmd.LoadPom(filepath.Join("test_data", "pom_flavour", "reactor", "pom.xml"))
+	require.NoError(t, err, "failed to load pom")
+
+	reactor := m.Reactor()
+	require.Len(t, reactor, 3, "reactor")
+	assert.Equal(t, "pom", m.Packaging(), "packaging")
+
+	service := reactor[2]
+	assert.Equal(t, "service", service.Project.ArtifactID, "module artifactId")
+	require.Len(t, service.Parents, 1, "parents of service")
+	assert.Equal(t, "17", service.JavaVersion(), "java version inherited from the parent")
+	assert.Equal(t, "1.0.0-SNAPSHOT", service.Resolve("${project.version}"), "project version inherited from the parent")
+
+	dropwizard := service.FindArtifact("io.dropwizard", "dropwizard-core")
+	require.NotNil(t, dropwizard, "dropwizard dependency with a property group")
+	assert.Equal(t, "4.0.2", dropwizard.Version, "dropwizard version")
+
+	m, err = importcmd.LoadPom(filepath.Join("test_data", "pom_flavour", "quarkus", "pom.xml"))
+	require.NoError(t, err, "failed to load pom")
+	frameworks := m.Frameworks()
+	require.Len(t, frameworks, 1, "frameworks")
+	assert.Equal(t, importcmd.QUARKUS, frameworks[0].Name, "framework")
+	assert.Equal(t, "3.6.4", frameworks[0].Version, "framework version")
+}
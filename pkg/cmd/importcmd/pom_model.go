@@ -0,0 +1,372 @@
+package importcmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/pkg/errors"
+)
+
+// PomProject the parts of a maven pom.xml used to detect the pack
+type PomProject struct {
+	XMLName              xml.Name        `xml:"project"`
+	GroupID              string          `xml:"groupId"`
+	ArtifactID           string          `xml:"artifactId"`
+	Version              string          `xml:"version"`
+	Packaging            string          `xml:"packaging"`
+	Parent               *PomParent      `xml:"parent"`
+	Properties           PomProperties   `xml:"properties"`
+	Modules              []string        `xml:"modules>module"`
+	Dependencies         []PomDependency `xml:"dependencies>dependency"`
+	DependencyManagement []PomDependency `xml:"dependencyManagement>dependencies>dependency"`
+	Plugins              []PomPlugin     `xml:"build>plugins>plugin"`
+	PluginManagement     []PomPlugin     `xml:"build>pluginManagement>plugins>plugin"`
+}
+
+// PomParent the parent of a pom.xml
+type PomParent struct {
+	GroupID      string  `xml:"groupId"`
+	ArtifactID   string  `xml:"artifactId"`
+	Version      string  `xml:"version"`
+	RelativePath *string `xml:"relativePath"`
+}
+
+// PomDependency a dependency of a pom.xml
+type PomDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Scope      string `xml:"scope"`
+	Type       string `xml:"type"`
+}
+
+// PomPlugin a build plugin of a pom.xml
+type PomPlugin struct {
+	GroupID       string                 `xml:"groupId"`
+	ArtifactID    string                 `xml:"artifactId"`
+	Version       string                 `xml:"version"`
+	Configuration PomPluginConfiguration `xml:"configuration"`
+}
+
+// PomPluginConfiguration the configuration of a build plugin used to find the java version
+type PomPluginConfiguration struct {
+	Release string `xml:"release"`
+	Source  string `xml:"source"`
+	Target  string `xml:"target"`
+}
+
+// PomProperties the properties of a pom.xml
+type PomProperties map[string]string
+
+// UnmarshalXML unmarshals the properties element into a map
+func (p *PomProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	if *p == nil {
+		*p = PomProperties{}
+	}
+	for {
+		t, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch e := t.(type) {
+		case xml.StartElement:
+			value := ""
+			err = d.DecodeElement(&value, &e)
+			if err != nil {
+				return err
+			}
+			(*p)[e.Name.Local] = strings.TrimSpace(value)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// PomModel a pom.xml with its local parents and the models of its modules
+type PomModel struct {
+	// Path the path of the pom.xml
+	Path string
+	// Project the project of the pom.xml
+	Project *PomProject
+	// Parents the local parent projects found via the relativePath starting with the nearest
+	Parents []*PomProject
+	// Modules the models of the modules of a multi-module reactor
+	Modules []*PomModel
+	// Properties the properties of the project and its parents
+	Properties map[string]string
+}
+
+var pomPropertyReference = regexp.MustCompile(`\$\{([^}]+)}`)
+
+// the maximum depth of parents and modules to avoid cycles
+const pomMaximumDepth = 10
+
+// ErrNotPom returned when a file is not a maven project
+var ErrNotPom = errors.New("not a maven project")
+
+// ParsePom parses the pom.xml data returning ErrNotPom if the root element is not a project
+func ParsePom(data []byte) (*PomProject, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Strict = false
+	d.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return nil, ErrNotPom
+		}
+		if err != nil {
+			return nil, err
+		}
+		e, ok := t.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if e.Name.Local != "project" {
+			return nil, ErrNotPom
+		}
+		project := &PomProject{}
+		err = d.DecodeElement(project, &e)
+		if err != nil {
+			return nil, err
+		}
+		return project, nil
+	}
+}
+
+// LoadPom loads the pom.xml with its local parents and modules
+func LoadPom(path string) (*PomModel, error) {
+	return loadPom(path, 0)
+}
+
+func loadPom(path string, depth int) (*PomModel, error) {
+	project, err := loadPomProject(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &PomModel{
+		Path:       path,
+		Project:    project,
+		Properties: map[string]string{},
+	}
+
+	// lets find the local parents so we can inherit their properties
+	dir := filepath.Dir(path)
+	p := project
+	for i := 0; i < pomMaximumDepth && p.Parent != nil; i++ {
+		relativePath := "../pom.xml"
+		if p.Parent.RelativePath != nil {
+			relativePath = strings.TrimSpace(*p.Parent.RelativePath)
+		}
+		if relativePath == "" {
+			break
+		}
+		parentPath := filepath.Join(dir, relativePath)
+		if exists, _ := files.DirExists(parentPath); exists {
+			parentPath = filepath.Join(parentPath, "pom.xml")
+		}
+		exists, err := files.FileExists(parentPath)
+		if err != nil {
+			return m, errors.Wrapf(err, "failed to check if file exists %s", parentPath)
+		}
+		if !exists {
+			break
+		}
+		parent, err := loadPomProject(parentPath)
+		if err != nil {
+			return m, errors.Wrapf(err, "failed to load parent pom.xml of %s", path)
+		}
+		if parent.ArtifactID != p.Parent.ArtifactID {
+			break
+		}
+		m.Parents = append(m.Parents, parent)
+		dir = filepath.Dir(parentPath)
+		p = parent
+	}
+
+	for i := len(m.Parents) - 1; i >= 0; i-- {
+		m.addProperties(m.Parents[i])
+	}
+	m.addProperties(project)
+
+	if depth < pomMaximumDepth {
+		for _, module := range project.Modules {
+			modulePath := filepath.Join(filepath.Dir(path), strings.TrimSpace(module))
+			if exists, _ := files.DirExists(modulePath); exists {
+				modulePath = filepath.Join(modulePath, "pom.xml")
+			}
+			exists, err := files.FileExists(modulePath)
+			if err != nil {
+				return m, errors.Wrapf(err, "failed to check if file exists %s", modulePath)
+			}
+			if !exists {
+				continue
+			}
+			child, err := loadPom(modulePath, depth+1)
+			if err != nil {
+				return m, errors.Wrapf(err, "failed to load module %s of %s", module, path)
+			}
+			m.Modules = append(m.Modules, child)
+		}
+	}
+	return m, nil
+}
+
+func loadPomProject(path string) (*PomProject, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read file %s", path)
+	}
+	project, err := ParsePom(data)
+	if err != nil {
+		if err == ErrNotPom {
+			return nil, err
+		}
+		return nil, errors.Wrapf(err, "failed to parse %s", path)
+	}
+	return project, nil
+}
+
+func (m *PomModel) addProperties(p *PomProject) {
+	for k, v := range p.Properties {
+		m.Properties[k] = v
+	}
+	groupID := p.GroupID
+	version := p.Version
+	if p.Parent != nil {
+		if groupID == "" {
+			groupID = p.Parent.GroupID
+		}
+		if version == "" {
+			version = p.Parent.Version
+		}
+		m.Properties["project.parent.groupId"] = p.Parent.GroupID
+		m.Properties["project.parent.artifactId"] = p.Parent.ArtifactID
+		m.Properties["project.parent.version"] = p.Parent.Version
+	}
+	m.Properties["project.groupId"] = groupID
+	m.Properties["project.artifactId"] = p.ArtifactID
+	m.Properties["project.version"] = version
+}
+
+// Resolve replaces the ${name} property references in the value
+func (m *PomModel) Resolve(value string) string {
+	value = strings.TrimSpace(value)
+	for i := 0; i < pomMaximumDepth && strings.Contains(value, "${"); i++ {
+		resolved := pomPropertyReference.ReplaceAllStringFunc(value, func(ref string) string {
+			name := ref[2 : len(ref)-1]
+			if v, ok := m.Properties[name]; ok {
+				return v
+			}
+			return ref
+		})
+		if resolved == value {
+			break
+		}
+		value = resolved
+	}
+	return value
+}
+
+// Property returns the resolved value of the property
+func (m *PomModel) Property(name string) (string, bool) {
+	v, ok := m.Properties[name]
+	if !ok {
+		return "", false
+	}
+	return m.Resolve(v), true
+}
+
+// Reactor returns this model and the models of all of its modules
+func (m *PomModel) Reactor() []*PomModel {
+	answer := []*PomModel{m}
+	for _, module := range m.Modules {
+		answer = append(answer, module.Reactor()...)
+	}
+	return answer
+}
+
+// Projects returns the project and its local parents
+func (m *PomModel) Projects() []*PomProject {
+	return append([]*PomProject{m.Project}, m.Parents...)
+}
+
+// Packaging returns the packaging of the project defaulting to jar
+func (m *PomModel) Packaging() string {
+	packaging := m.Resolve(m.Project.Packaging)
+	if packaging == "" {
+		return "jar"
+	}
+	return packaging
+}
+
+// Artifacts returns the resolved parent, dependencies, managed dependencies and plugins of the project and its local parents
+func (m *PomModel) Artifacts() []PomDependency {
+	var answer []PomDependency
+	add := func(groupID, artifactID, version string) {
+		answer = append(answer, PomDependency{
+			GroupID:    m.Resolve(groupID),
+			ArtifactID: m.Resolve(artifactID),
+			Version:    m.Resolve(version),
+		})
+	}
+	for _, p := range m.Projects() {
+		if p.Parent != nil {
+			add(p.Parent.GroupID, p.Parent.ArtifactID, p.Parent.Version)
+		}
+		for _, d := range append(append([]PomDependency{}, p.Dependencies...), p.DependencyManagement...) {
+			add(d.GroupID, d.ArtifactID, d.Version)
+		}
+		for _, pl := range append(append([]PomPlugin{}, p.Plugins...), p.PluginManagement...) {
+			add(pl.GroupID, pl.ArtifactID, pl.Version)
+		}
+	}
+	return answer
+}
+
+// FindArtifact returns the first artifact whose group starts with the prefix and whose artifact id matches if specified
+func (m *PomModel) FindArtifact(groupPrefix, artifactID string) *PomDependency {
+	for _, a := range m.Artifacts() {
+		if strings.HasPrefix(a.GroupID, groupPrefix) && (artifactID == "" || a.ArtifactID == artifactID) {
+			a := a
+			return &a
+		}
+	}
+	return nil
+}
+
+// JavaVersion returns the major java version from the properties or the maven-compiler-plugin configuration
+func (m *PomModel) JavaVersion() string {
+	// java.version is used by Spring Boot, maven-compiler-plugin 3.6 and later versions supports and recommends
+	// maven.compiler.release and older versions use maven.compiler.target
+	for _, name := range []string{"java.version", "maven.compiler.release", "maven.compiler.target", "maven.compiler.source"} {
+		v, ok := m.Property(name)
+		if ok {
+			version := normaliseJavaVersion(v)
+			if version != "" {
+				return version
+			}
+		}
+	}
+	for _, p := range m.Projects() {
+		for _, pl := range append(append([]PomPlugin{}, p.Plugins...), p.PluginManagement...) {
+			if pl.ArtifactID != "maven-compiler-plugin" {
+				continue
+			}
+			for _, v := range []string{pl.Configuration.Release, pl.Configuration.Target, pl.Configuration.Source} {
+				version := normaliseJavaVersion(m.Resolve(v))
+				if version != "" {
+					return version
+				}
+			}
+		}
+	}
+	return ""
+}
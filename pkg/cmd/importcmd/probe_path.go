@@ -0,0 +1,190 @@
+package importcmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/pkg/errors"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// SpringBootProbePath returns the path of the health endpoint of a Spring Boot application using the actuator in the
+// maven project from its application properties or an empty string if the project does not use the actuator
+func SpringBootProbePath(pomPath string) (string, error) {
+	m, err := LoadPom(pomPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to load %s", pomPath)
+	}
+	legacy := false
+	for _, f := range m.Frameworks() {
+		if f.Name == SPRINGBOOT && strings.HasPrefix(f.Version, "1.") {
+			legacy = true
+		}
+	}
+	for _, r := range m.Reactor() {
+		if !r.hasDependency("org.springframework.boot", "spring-boot-starter-actuator") {
+			continue
+		}
+		props, err := loadSpringProperties(filepath.Join(filepath.Dir(r.Path), "src", "main", "resources"))
+		if err != nil {
+			return "", err
+		}
+		return springBootProbePath(props, legacy), nil
+	}
+	return "", nil
+}
+
+// springBootProbePath returns the health endpoint path from the context path and actuator properties
+func springBootProbePath(props map[string]string, legacy bool) string {
+	if legacy {
+		// Spring Boot 1.x
+		contextPath := props["server.context-path"]
+		if managementPort(props, "management.port") {
+			contextPath = ""
+		}
+		return path.Join("/", contextPath, props["management.context-path"], "health")
+	}
+
+	// the servlet context path does not apply when the actuator uses its own port
+	contextPath := props["server.servlet.context-path"]
+	if managementPort(props, "management.server.port") {
+		contextPath = props["management.server.base-path"]
+		if contextPath == "" {
+			contextPath = props["management.server.servlet.context-path"]
+		}
+	}
+	basePath, ok := props["management.endpoints.web.base-path"]
+	if !ok {
+		basePath = "/actuator"
+	}
+	return path.Join("/", contextPath, basePath, "health")
+}
+
+// managementPort returns true if the actuator is configured to use a different port to the application
+func managementPort(props map[string]string, name string) bool {
+	port := props[name]
+	return port != "" && port != props["server.port"]
+}
+
+func (m *PomModel) hasDependency(groupID, artifactID string) bool {
+	for _, p := range m.Projects() {
+		for _, d := range p.Dependencies {
+			if m.Resolve(d.GroupID) == groupID && m.Resolve(d.ArtifactID) == artifactID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// loadSpringProperties loads the application.properties, application.yml and application.yaml files in the dir
+// ignoring any properties which use placeholders or are only enabled for specific profiles
+func loadSpringProperties(dir string) (map[string]string, error) {
+	props := map[string]string{}
+	for _, name := range []string{"application.yaml", "application.yml", "application.properties"} {
+		fileName := filepath.Join(dir, name)
+		exists, err := files.FileExists(fileName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to check if file exists %s", fileName)
+		}
+		if !exists {
+			continue
+		}
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read file %s", fileName)
+		}
+		if strings.HasSuffix(name, ".properties") {
+			parseProperties(string(data), props)
+			continue
+		}
+		for _, doc := range strings.Split(string(data), "\n---") {
+			values := map[string]interface{}{}
+			err = yaml.Unmarshal([]byte(doc), &values)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse YAML file %s", fileName)
+			}
+			docProps := map[string]string{}
+			flattenProperties("", values, docProps)
+			if docProps["spring.profiles"] != "" || docProps["spring.config.activate.on-profile"] != "" {
+				continue
+			}
+			for k, v := range docProps {
+				props[k] = v
+			}
+		}
+	}
+	for k, v := range props {
+		if strings.Contains(v, "${") {
+			delete(props, k)
+		}
+	}
+	return props, nil
+}
+
+func parseProperties(text string, props map[string]string) {
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		i := strings.IndexAny(line, "=:")
+		if i < 0 {
+			continue
+		}
+		props[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+	}
+}
+
+func flattenProperties(prefix string, values map[string]interface{}, props map[string]string) {
+	for k, v := range values {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch x := v.(type) {
+		case map[string]interface{}:
+			flattenProperties(key, x, props)
+		case nil:
+			props[key] = ""
+		default:
+			props[key] = fmt.Sprintf("%v", x)
+		}
+	}
+}
+
+// FixProbePath sets the probePath in the values.yaml of the application chart to the health endpoint of the
+// Spring Boot actuator returning true if the chart was modified
+func (o *ImportOptions) FixProbePath() (bool, error) {
+	probePath, err := SpringBootProbePath(filepath.Join(o.Dir, "pom.xml"))
+	if err != nil {
+		return false, err
+	}
+	if probePath == "" {
+		return false, nil
+	}
+	modified := false
+	valuesFile := filepath.Join(o.Dir, ChartsDir, o.AppName, "values.yaml")
+	err = modifyYAMLFile(valuesFile, func(node *kyaml.Node) bool {
+		if node.Kind != kyaml.MappingNode {
+			return false
+		}
+		if mapValue(node, "probePath") == nil {
+			node.Content = append(node.Content,
+				&kyaml.Node{Kind: kyaml.ScalarNode, Tag: kyaml.NodeTagString, Value: "probePath"},
+				&kyaml.Node{Kind: kyaml.ScalarNode, Tag: kyaml.NodeTagString, Value: probePath})
+			modified = true
+			return true
+		}
+		modified = setMapValue(node, "probePath", probePath)
+		return modified
+	})
+	return modified, err
+}
@@ -0,0 +1,169 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	springBootPom = `<project>
+  <parent>
+    <groupId>org.springframework.boot</groupId>
+    <artifactId>spring-boot-starter-parent</artifactId>
+    <version>%s</version>
+  </parent>
+  <artifactId>demo</artifactId>
+  <dependencies>
+    <dependency>
+      <groupId>org.springframework.boot</groupId>
+      <artifactId>spring-boot-starter-actuator</artifactId>
+    </dependency>
+  </dependencies>
+</project>
+`
+	springBoot3 = "3.3.0"
+)
+
+func TestSpringBootProbePath(t *testing.T) {
+	testCases := []struct {
+		name       string
+		version    string
+		noActuator bool
+		files      map[string]string
+		expected   string
+	}{
+		{
+			name:     "defaults",
+			version:  springBoot3,
+			expected: "/actuator/health",
+		},
+		{
+			name:    "context-path",
+			version: springBoot3,
+			files: map[string]string{
+				"application.properties": "# the context\nserver.servlet.context-path=/api\nmanagement.endpoints.web.base-path = /manage\n",
+			},
+			expected: "/api/manage/health",
+		},
+		{
+			name:    "yaml",
+			version: springBoot3,
+			files: map[string]string{
+				"application.yml": `server:
+  servlet:
+    context-path: /api
+---
+spring:
+  config:
+    activate:
+      on-profile: dev
+server:
+  servlet:
+    context-path: /dev
+`,
+			},
+			expected: "/api/actuator/health",
+		},
+		{
+			name:    "management-port",
+			version: springBoot3,
+			files: map[string]string{
+				"application.yaml": `server:
+  port: 8080
+  servlet:
+    context-path: /api
+management:
+  server:
+    port: 8081
+    base-path: /admin
+`,
+			},
+			expected: "/admin/actuator/health",
+		},
+		{
+			name:    "placeholder",
+			version: springBoot3,
+			files: map[string]string{
+				"application.properties": "server.servlet.context-path=${CONTEXT_PATH}\n",
+			},
+			expected: "/actuator/health",
+		},
+		{
+			name:    "spring-boot-1",
+			version: "1.5.22.RELEASE",
+			files: map[string]string{
+				"application.properties": "server.context-path=/api\nmanagement.context-path=/admin\n",
+			},
+			expected: "/api/admin/health",
+		},
+		{
+			name:       "no-actuator",
+			version:    springBoot3,
+			noActuator: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			pom := fmt.Sprintf(springBootPom, tc.version)
+			if tc.noActuator {
+				pom = strings.ReplaceAll(pom, "spring-boot-starter-actuator", "spring-boot-starter-web")
+			}
+			fileMap := map[string]string{"pom.xml": pom}
+			for name, text := range tc.files {
+				fileMap[filepath.Join("src", "main", "resources", name)] = text
+			}
+			writeTestFiles(t, dir, fileMap)
+
+			probePath, err := importcmd.SpringBootProbePath(filepath.Join(dir, "pom.xml"))
+			require.NoError(t, err, "failed to find the probe path")
+			assert.Equal(t, tc.expected, probePath)
+		})
+	}
+}
+
+func TestFixProbePath(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"pom.xml": fmt.Sprintf(springBootPom, springBoot3),
+		"src/main/resources/application.properties": "server.servlet.context-path=/api\n",
+		"charts/myapp/values.yaml": `# the probe
+probePath: /health
+service:
+  port: 80
+`,
+		"charts/another/values.yaml": "service:\n  port: 80\n",
+	})
+
+	o := &importcmd.ImportOptions{}
+	o.Dir = dir
+	o.AppName = "myapp"
+	modified, err := o.FixProbePath()
+	require.NoError(t, err, "failed to fix the probe path")
+	assert.True(t, modified, "should have modified the chart")
+	assertFileEquals(t, filepath.Join(dir, "charts", "myapp", "values.yaml"), `# the probe
+probePath: /api/actuator/health
+service:
+  port: 80
+`)
+
+	modified, err = o.FixProbePath()
+	require.NoError(t, err, "failed to fix the probe path")
+	assert.False(t, modified, "should not modify the chart again")
+
+	o.AppName = "another"
+	modified, err = o.FixProbePath()
+	require.NoError(t, err, "failed to fix the probe path")
+	assert.True(t, modified, "should have added the probe path")
+	assertFileEquals(t, filepath.Join(dir, "charts", "another", "values.yaml"), "service:\n  port: 80\nprobePath: /api/actuator/health\n")
+}
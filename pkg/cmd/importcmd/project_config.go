@@ -0,0 +1,223 @@
+package importcmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/constants"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/yamls"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// ProjectConfigFileName the name of the file in the .jx directory of a repository which configures how it is imported
+const ProjectConfigFileName = "project.yaml"
+
+// ProjectConfig the declarative configuration of an import which is usually checked into the repository as
+// .jx/project.yaml so that imports are reproducible and reviewable.
+//
+// Each field corresponds to the command line flag of the same name. Values are resolved in this order of precedence:
+//
+//  1. command line flags which are explicitly specified
+//  2. the project configuration file
+//  3. the team settings of the dev Environment
+//  4. the default values of the command line flags
+type ProjectConfig struct {
+	// Org the git organisation to import the project into
+	Org string `json:"org,omitempty"`
+	// Name the git repository name to import the project into
+	Name string `json:"name,omitempty"`
+	// Pack the name of the pipeline catalog pack to use
+	Pack string `json:"pack,omitempty"`
+	// NoPack disables defaulting a Dockerfile and Helm Chart from the pipeline catalog pack
+	NoPack *bool `json:"noPack,omitempty"`
+	// Monorepo whether to apply a pack to each service in a subdirectory of the MonorepoDir
+	Monorepo *bool `json:"monorepo,omitempty"`
+	// MonorepoDir the directory containing a subdirectory for each service of a monorepo
+	MonorepoDir string `json:"monorepoDir,omitempty"`
+	// NoMavenFix disables fixing an existing pom.xml
+	NoMavenFix *bool `json:"noMavenFix,omitempty"`
+	// DeployKind the kind of deployment to use for the project
+	DeployKind string `json:"deployKind,omitempty"`
+	// Canary whether to use canary rollouts
+	Canary *bool `json:"canary,omitempty"`
+	// HPA whether to enable the Horizontal Pod Autoscaler
+	HPA *bool `json:"hpa,omitempty"`
+	// Scheduler the lighthouse scheduler to use
+	Scheduler string `json:"scheduler,omitempty"`
+	// DockerRegistryOrg the docker registry organisation to use
+	DockerRegistryOrg string `json:"dockerRegistryOrg,omitempty"`
+	// DockerRegistry the docker registry host to use
+	DockerRegistry string `json:"dockerRegistry,omitempty"`
+	// Placeholders the extra placeholders to replace in the source code. Values specified via --set-placeholder take precedence
+	Placeholders map[string]string `json:"placeholders,omitempty"`
+	// ImportCommitMessage the initial commit message used when importing the project
+	ImportCommitMessage string `json:"importCommitMessage,omitempty"`
+	// ServiceAccount the Kubernetes ServiceAccount to use to run the initial pipeline
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// Private whether a new git repository should be private. Defaults to the team settings
+	Private *bool `json:"private,omitempty"`
+	// EnvName the name of the environment to create (only used for env projects)
+	EnvName string `json:"envName,omitempty"`
+	// EnvStrategy the promotion strategy of the environment to create (only used for env projects)
+	EnvStrategy string `json:"envStrategy,omitempty"`
+	// NestedRepo whether nested repositories are used (in gitlab)
+	NestedRepo *bool `json:"nestedRepo,omitempty"`
+	// NoCollaborator disables checking if the bot user is a collaborator
+	NoCollaborator *bool `json:"noCollaborator,omitempty"`
+	// GithubApp whether lighthouse uses a GitHub App rather than a bot user
+	GithubApp *bool `json:"githubApp,omitempty"`
+	// GithubAppName the name of the GitHub App used by lighthouse
+	GithubAppName string `json:"githubAppName,omitempty"`
+	// WaitForPR waits for the Pull Request on the cluster environment git repository to merge
+	WaitForPR *bool `json:"waitForPr,omitempty"`
+	// NoDevPR disables generating a Pull Request on the cluster git repository
+	NoDevPR *bool `json:"noDevPr,omitempty"`
+	// NoStart disables starting a release pipeline
+	NoStart *bool `json:"noStart,omitempty"`
+	// PRPollPeriod the time between polls of the Pull Request on the cluster environment git repository
+	PRPollPeriod string `json:"prPollPeriod,omitempty"`
+	// PRPollTimeout the maximum amount of time we wait for the Pull Request on the cluster environment git repository
+	PRPollTimeout string `json:"prPollTimeout,omitempty"`
+	// Jenkins the name of the Jenkins server to import the project into
+	Jenkins string `json:"jenkins,omitempty"`
+	// JenkinsfileRunner the container image to use with Jenkinsfilerunner
+	JenkinsfileRunner string `json:"jenkinsfilerunner,omitempty"`
+}
+
+// FlagChanged returns true if the given command line flag was explicitly specified
+func (o *ImportOptions) FlagChanged(name string) bool {
+	if o.Cmd == nil {
+		return false
+	}
+	f := o.Cmd.Flags().Lookup(name)
+	return f != nil && f.Changed
+}
+
+// LoadProjectConfig loads the project configuration from the --config file or the .jx/project.yaml file in the
+// given directory. When importing the repositories of an organisation the --config file is shared by every repository
+// and the values in the .jx/project.yaml file of a repository take precedence. Returns nil if there is no configuration
+func (o *ImportOptions) LoadProjectConfig(dir string) (*ProjectConfig, error) {
+	config, err := copyProjectConfig(o.parentProjectConfig)
+	if err != nil {
+		return nil, err
+	}
+	path := o.ProjectConfigFile
+	if path == "" {
+		if dir == "" {
+			return config, nil
+		}
+		path = filepath.Join(dir, ".jx", ProjectConfigFileName)
+		exists, err := files.FileExists(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to check if file exists %s", path)
+		}
+		if !exists {
+			return config, nil
+		}
+	}
+	if config == nil {
+		config = &ProjectConfig{}
+	}
+	err = yamls.LoadFile(path, config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load project configuration file %s", path)
+	}
+	log.Logger().Infof("using the project configuration file %s", termcolor.ColorInfo(path))
+	return config, nil
+}
+
+// copyProjectConfig returns a deep copy of the project configuration so that loading a file over it does not modify it
+func copyProjectConfig(config *ProjectConfig) (*ProjectConfig, error) {
+	if config == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal project configuration")
+	}
+	answer := &ProjectConfig{}
+	err = json.Unmarshal(data, answer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal project configuration")
+	}
+	return answer, nil
+}
+
+// ApplyProjectConfig applies the project configuration to any options which were not explicitly specified
+// on the command line
+func (o *ImportOptions) ApplyProjectConfig(config *ProjectConfig) error {
+	if config == nil {
+		return nil
+	}
+	o.projectConfig = config
+
+	setString := func(flag string, value string, target *string) {
+		if value != "" && !o.FlagChanged(flag) {
+			*target = value
+		}
+	}
+	setBool := func(flag string, value *bool, target *bool) {
+		if value != nil && !o.FlagChanged(flag) {
+			*target = *value
+		}
+	}
+	setDuration := func(flag string, value string, target *time.Duration) error {
+		if value == "" || o.FlagChanged(flag) {
+			return nil
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse %s duration %s", flag, value)
+		}
+		*target = d
+		return nil
+	}
+
+	setString("org", config.Org, &o.Organisation)
+	setString("name", config.Name, &o.Repository)
+	setString("pack", config.Pack, &o.Pack)
+	setBool("no-pack", config.NoPack, &o.DisableBuildPack)
+	setBool("monorepo", config.Monorepo, &o.Monorepo)
+	setString("monorepo-dir", config.MonorepoDir, &o.MonorepoDir)
+	setBool("no-maven-fix", config.NoMavenFix, &o.DisableMaven)
+	setString("deploy-kind", config.DeployKind, &o.DeployKind)
+	setBool(constants.OptionCanary, config.Canary, &o.DeployOptions.Canary)
+	setBool(constants.OptionHPA, config.HPA, &o.DeployOptions.HPA)
+	setString("scheduler", config.Scheduler, &o.SchedulerName)
+	setString("docker-registry-org", config.DockerRegistryOrg, &o.DockerRegistryOrg)
+	setString("docker-registry", config.DockerRegistry, &o.DockerRegistry)
+	setString("import-commit-message", config.ImportCommitMessage, &o.ImportGitCommitMessage)
+	setString("service-account", config.ServiceAccount, &o.ServiceAccount)
+	setString("env-name", config.EnvName, &o.EnvName)
+	setString("env-strategy", config.EnvStrategy, &o.EnvStrategy)
+	setBool("nested-repo", config.NestedRepo, &o.NestedRepo)
+	setBool("no-collaborator", config.NoCollaborator, &o.IgnoreCollaborator)
+	setBool("github-app", config.GithubApp, &o.GithubAppMode)
+	setString("github-app-name", config.GithubAppName, &o.GithubAppName)
+	setBool("wait-for-pr", config.WaitForPR, &o.WaitForSourceRepositoryPullRequest)
+	setBool("no-dev-pr", config.NoDevPR, &o.NoDevPullRequest)
+	setBool("no-start", config.NoStart, &o.DisableStartPipeline)
+	setString("jenkins", config.Jenkins, &o.Destination.Jenkins.Server)
+	setString("jenkinsfilerunner", config.JenkinsfileRunner, &o.Destination.JenkinsfileRunner.Image)
+	if config.Private != nil {
+		o.GitRepositoryOptions.Private = *config.Private
+	}
+	err := setDuration("pr-poll-period", config.PRPollPeriod, &o.PullRequestPollPeriod)
+	if err != nil {
+		return err
+	}
+	return setDuration("pr-poll-timeout", config.PRPollTimeout, &o.PullRequestPollTimeout)
+}
+
+// loadAndApplyProjectConfig loads the project configuration if there is one and applies it
+func (o *ImportOptions) loadAndApplyProjectConfig(dir string) error {
+	config, err := o.LoadProjectConfig(dir)
+	if err != nil {
+		return err
+	}
+	return o.ApplyProjectConfig(config)
+}
@@ -0,0 +1,108 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectConfigPrecedence(t *testing.T) {
+	cmd, o := importcmd.NewCmdImportAndOptions()
+	err := cmd.Flags().Parse([]string{"--pack", "go", "--no-collaborator"})
+	require.NoError(t, err, "failed to parse flags")
+
+	config, err := o.LoadProjectConfig(filepath.Join("test_data", "project_config"))
+	require.NoError(t, err, "failed to load project config")
+	require.NotNil(t, config, "no project config found")
+
+	err = o.ApplyProjectConfig(config)
+	require.NoError(t, err, "failed to apply project config")
+
+	err = o.DefaultValuesFromTeamSettings(&v1.TeamSettings{
+		DeployKind:        "default",
+		DockerRegistryOrg: "team-registry-org",
+		GitPublic:         false,
+	})
+	require.NoError(t, err, "failed to default from team settings")
+
+	// flags take precedence over the project config
+	assert.Equal(t, "go", o.Pack, "o.Pack")
+	assert.True(t, o.IgnoreCollaborator, "o.IgnoreCollaborator")
+
+	// project config takes precedence over team settings and flag defaults
+	assert.Equal(t, "knative", o.DeployKind, "o.DeployKind")
+	assert.True(t, o.DeployOptions.Canary, "o.DeployOptions.Canary")
+	assert.Equal(t, "my-scheduler", o.SchedulerName, "o.SchedulerName")
+	assert.False(t, o.GitRepositoryOptions.Private, "o.GitRepositoryOptions.Private")
+	assert.True(t, o.DisableStartPipeline, "o.DisableStartPipeline")
+	assert.Equal(t, 5*time.Second, o.PullRequestPollPeriod, "o.PullRequestPollPeriod")
+
+	// team settings take precedence over flag defaults
+	assert.Equal(t, "team-registry-org", o.DockerRegistryOrg, "o.DockerRegistryOrg")
+	assert.Equal(t, 20*time.Minute, o.PullRequestPollTimeout, "o.PullRequestPollTimeout")
+}
+
+func TestTeamDeployOptions(t *testing.T) {
+	teamSettings := &v1.TeamSettings{
+		DeployKind:    "knative",
+		DeployOptions: &v1.DeployOptions{Canary: true, HPA: true},
+	}
+
+	_, o := importcmd.NewCmdImportAndOptions()
+	err := o.DefaultValuesFromTeamSettings(teamSettings)
+	require.NoError(t, err, "failed to default from team settings")
+	assert.Equal(t, "knative", o.DeployKind, "o.DeployKind")
+	assert.True(t, o.DeployOptions.Canary, "o.DeployOptions.Canary")
+	assert.True(t, o.DeployOptions.HPA, "o.DeployOptions.HPA")
+
+	// flags which are specified take precedence over the team settings
+	cmd, o := importcmd.NewCmdImportAndOptions()
+	err = cmd.Flags().Parse([]string{"--deploy-kind", "default", "--canary=false"})
+	require.NoError(t, err, "failed to parse flags")
+	err = o.DefaultValuesFromTeamSettings(teamSettings)
+	require.NoError(t, err, "failed to default from team settings")
+	assert.Equal(t, "default", o.DeployKind, "o.DeployKind")
+	assert.False(t, o.DeployOptions.Canary, "o.DeployOptions.Canary")
+	assert.True(t, o.DeployOptions.HPA, "o.DeployOptions.HPA")
+}
+
+func TestProjectConfigMissing(t *testing.T) {
+	o := &importcmd.ImportOptions{}
+	config, err := o.LoadProjectConfig(t.TempDir())
+	require.NoError(t, err, "failed to load project config")
+	assert.Nil(t, config, "should not find a project config")
+}
+
+func TestProjectConfigImportCommitMessage(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		".jx/project.yaml": "importCommitMessage: 'chore: import from project config'\n",
+		"main.go":          "package main\n",
+	})
+
+	_, o := importcmd.NewCmdImportAndOptions()
+	o.Dir = dir
+	o.BatchMode = true
+	o.DisableDotGitSearch = true
+	err := o.DiscoverProject()
+	require.NoError(t, err, "failed to discover the project")
+
+	message, err := cli.NewCLIClient("", nil).Command(dir, "log", "-1", "--format=%s")
+	require.NoError(t, err, "failed to get the commit message")
+	assert.Equal(t, "chore: import from project config", strings.TrimSpace(message))
+}
@@ -0,0 +1,233 @@
+package importcmd
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/pkg/errors"
+)
+
+const (
+	PYTHON  = "python"
+	DJANGO  = "django"
+	FASTAPI = "fastapi"
+	FLASK   = "flask"
+)
+
+// PythonProjectFiles the files which indicate a python project
+var PythonProjectFiles = []string{"pyproject.toml", "requirements.txt", "Pipfile"}
+
+// the frameworks of python pack variants in order of precedence
+var pythonFrameworks = []string{DJANGO, FASTAPI, FLASK}
+
+var (
+	pythonVersion        = regexp.MustCompile(`(\d+)\.(\d+)`)
+	pythonDependencyName = regexp.MustCompile(`^\s*["']?([A-Za-z0-9][A-Za-z0-9._-]*)`)
+	pythonQuotedString   = regexp.MustCompile(`["']([^"']+)["']`)
+	tomlSection          = regexp.MustCompile(`^\s*\[\[?([^\]]+)\]\]?\s*(#.*)?$`)
+	tomlKeyValue         = regexp.MustCompile(`^\s*["']?([A-Za-z0-9._-]+)["']?\s*=\s*(.*)$`)
+)
+
+// HasPythonProjectFile returns true if there is a python project file in the directory
+func HasPythonProjectFile(dir string) (bool, error) {
+	for _, name := range PythonProjectFiles {
+		path := filepath.Join(dir, name)
+		exists, err := files.FileExists(path)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to check if file exists %s", path)
+		}
+		if exists {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// PythonProject the python version and dependencies found in the project files
+type PythonProject struct {
+	// Version the major and minor python version like 3.11
+	Version string
+	// Dependencies the lower case names of the dependencies
+	Dependencies map[string]bool
+}
+
+// PythonFlavour returns the python pack to use for the project files in the directory.
+//
+// Django, FastAPI and Flask packs are used if the catalog has them then a pythonX.Y pack for the python version of the
+// .python-version file, Pipfile or pyproject.toml, otherwise the python pack
+func PythonFlavour(packsDir, dir string) (string, error) {
+	p, err := LoadPythonProject(dir)
+	if err != nil {
+		return "", err
+	}
+	packExists := func(pack string) bool {
+		exists, _ := files.DirExists(filepath.Join(packsDir, pack))
+		return exists
+	}
+	for _, framework := range pythonFrameworks {
+		if !p.Dependencies[framework] {
+			continue
+		}
+		for _, pack := range []string{PYTHON + "-" + framework, framework} {
+			if packExists(pack) {
+				return pack, nil
+			}
+		}
+	}
+	if p.Version != "" {
+		pack := PYTHON + p.Version
+		if packExists(pack) {
+			return pack, nil
+		}
+	}
+	return PYTHON, nil
+}
+
+// LoadPythonProject loads the python version and dependencies from the pyproject.toml, requirements.txt, Pipfile and
+// .python-version files in the directory
+func LoadPythonProject(dir string) (*PythonProject, error) {
+	p := &PythonProject{
+		Dependencies: map[string]bool{},
+	}
+	var versions []string
+
+	s, err := readOptionalFile(filepath.Join(dir, ".python-version"))
+	if err != nil {
+		return p, err
+	}
+	versions = append(versions, s)
+
+	s, err = readOptionalFile(filepath.Join(dir, "Pipfile"))
+	if err != nil {
+		return p, err
+	}
+	err = parseToml(s, func(section, key, value string) {
+		switch {
+		case section == "requires" && key == "python_version":
+			versions = append(versions, value)
+		case section == "packages" || section == "dev-packages":
+			p.addDependency(key)
+		}
+	})
+	if err != nil {
+		return p, errors.Wrapf(err, "failed to parse Pipfile in %s", dir)
+	}
+
+	s, err = readOptionalFile(filepath.Join(dir, "pyproject.toml"))
+	if err != nil {
+		return p, err
+	}
+	requiresPython := ""
+	err = parseToml(s, func(section, key, value string) {
+		switch {
+		case section == "tool.poetry.dependencies" && key == "python":
+			versions = append(versions, value)
+		case section == "tool.poetry.dependencies" || section == "tool.poetry.dev-dependencies" ||
+			(strings.HasPrefix(section, "tool.poetry.group.") && strings.HasSuffix(section, ".dependencies")):
+			p.addDependency(key)
+		case section == "project" && key == "requires-python":
+			requiresPython = value
+		case (section == "project" && key == "dependencies") || section == "project.optional-dependencies":
+			for _, m := range pythonQuotedString.FindAllStringSubmatch(value, -1) {
+				p.addDependency(m[1])
+			}
+		}
+	})
+	if err != nil {
+		return p, errors.Wrapf(err, "failed to parse pyproject.toml in %s", dir)
+	}
+	versions = append(versions, requiresPython)
+
+	s, err = readOptionalFile(filepath.Join(dir, "requirements.txt"))
+	if err != nil {
+		return p, err
+	}
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		p.addDependency(line)
+	}
+
+	for _, v := range versions {
+		m := pythonVersion.FindStringSubmatch(v)
+		if m != nil {
+			p.Version = m[1] + "." + m[2]
+			break
+		}
+	}
+	return p, nil
+}
+
+// addDependency adds the name of a dependency from a requirement like Django>=4.2 or fastapi[all]
+func (p *PythonProject) addDependency(requirement string) {
+	m := pythonDependencyName.FindStringSubmatch(requirement)
+	if m != nil {
+		p.Dependencies[strings.ToLower(m[1])] = true
+	}
+}
+
+// parseToml invokes the function for the top level keys of each table in a TOML document.
+//
+// This is not a full TOML parser; it only supports what is needed to find the python version and dependencies. Values
+// of multi line arrays are joined into a single value
+func parseToml(s string, fn func(section, key, value string)) error {
+	section := ""
+	key := ""
+	value := ""
+	depth := 0
+	for _, line := range strings.Split(s, "\n") {
+		if depth > 0 {
+			value += " " + line
+			depth += strings.Count(line, "[") - strings.Count(line, "]")
+			if depth <= 0 {
+				depth = 0
+				fn(section, key, value)
+			}
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if m := tomlSection.FindStringSubmatch(trimmed); m != nil {
+			section = strings.TrimSpace(m[1])
+			continue
+		}
+		m := tomlKeyValue.FindStringSubmatch(trimmed)
+		if m == nil {
+			continue
+		}
+		key = m[1]
+		value = strings.TrimSpace(m[2])
+		depth = strings.Count(value, "[") - strings.Count(value, "]")
+		if depth <= 0 {
+			depth = 0
+			fn(section, key, value)
+		}
+	}
+	if depth > 0 {
+		return errors.Errorf("unterminated array for key %s in section %s", key, section)
+	}
+	return nil
+}
+
+// readOptionalFile returns the contents of the file or an empty string if it does not exist
+func readOptionalFile(path string) (string, error) {
+	exists, err := files.FileExists(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to check if file exists %s", path)
+	}
+	if !exists {
+		return "", nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read file %s", path)
+	}
+	return string(b), nil
+}
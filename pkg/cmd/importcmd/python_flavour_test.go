@@ -0,0 +1,102 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPythonFlavour(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		packs    []string
+		expected string
+	}{
+		{
+			name:     "default",
+			files:    map[string]string{"requirements.txt": "requests==2.31.0\n"},
+			packs:    []string{"python-flask"},
+			expected: importcmd.PYTHON,
+		},
+		{
+			name: "requirements with python version",
+			files: map[string]string{
+				"requirements.txt": "# web\nFlask>=3.0\ngunicorn\n",
+				".python-version":  "3.11.4\n",
+			},
+			packs:    []string{"python3.11"},
+			expected: "python3.11",
+		},
+		{
+			name:     "requirements flask",
+			files:    map[string]string{"requirements.txt": "-r base.txt\nFlask>=3.0\n"},
+			packs:    []string{"python-flask", "python3.11"},
+			expected: "python-flask",
+		},
+		{
+			name:     "pep 621",
+			files:    map[string]string{"pyproject.toml": "[project]\nname = \"myapp\"\nrequires-python = \">=3.10\"\ndependencies = [\n  \"fastapi[all]>=0.100\",\n  \"uvicorn\",\n]\n"},
+			packs:    []string{"python-fastapi"},
+			expected: "python-fastapi",
+		},
+		{
+			name:     "pep 621 python version",
+			files:    map[string]string{"pyproject.toml": "[project]\nrequires-python = \">=3.10,<3.13\"\ndependencies = [\"requests\"]\n"},
+			packs:    []string{"python3.10", "python3.12"},
+			expected: "python3.10",
+		},
+		{
+			name:     "poetry",
+			files:    map[string]string{"pyproject.toml": "[tool.poetry.dependencies]\npython = \"^3.12\"\nDjango = \"^5.0\"\n\n[build-system]\nrequires = [\"poetry-core\"]\n"},
+			packs:    []string{importcmd.DJANGO, "python3.12"},
+			expected: importcmd.DJANGO,
+		},
+		{
+			name:     "poetry framework missing from catalog",
+			files:    map[string]string{"pyproject.toml": "[tool.poetry.dependencies]\npython = \"^3.12\"\nDjango = \"^5.0\"\n"},
+			packs:    []string{"python3.12"},
+			expected: "python3.12",
+		},
+		{
+			name:     "pipfile",
+			files:    map[string]string{"Pipfile": "[packages]\nflask = \"*\"\n\n[requires]\npython_version = \"3.9\"\n"},
+			packs:    []string{"python3.9"},
+			expected: "python3.9",
+		},
+		{
+			name: "python version file takes precedence",
+			files: map[string]string{
+				"Pipfile":         "[requires]\npython_version = \"3.9\"\n",
+				".python-version": "3.12\n",
+			},
+			packs:    []string{"python3.9", "python3.12"},
+			expected: "python3.12",
+		},
+	}
+	for _, tc := range testCases {
+		dir := t.TempDir()
+		for name, content := range tc.files {
+			err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600)
+			require.NoError(t, err, "failed to write %s for %s", name, tc.name)
+		}
+		packsDir := t.TempDir()
+		for _, pack := range tc.packs {
+			err := os.Mkdir(filepath.Join(packsDir, pack), 0700)
+			require.NoError(t, err, "failed to create pack %s for %s", pack, tc.name)
+		}
+
+		flavour, err := importcmd.PythonFlavour(packsDir, dir)
+		require.NoError(t, err, "failed to detect flavour for %s", tc.name)
+		assert.Equal(t, tc.expected, flavour, "flavour for %s", tc.name)
+	}
+}
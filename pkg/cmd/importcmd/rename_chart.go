@@ -0,0 +1,250 @@
+package importcmd
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// previewChartName the name of the preview chart folder which is not renamed
+const previewChartName = "preview"
+
+// RenameChartToMatchAppName renames the application chart folder and its references to match the app name
+func (o *ImportOptions) RenameChartToMatchAppName() error {
+	chartsDir := filepath.Join(o.Dir, ChartsDir)
+	exists, err := files.DirExists(chartsDir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if the charts directory exists %s", chartsDir)
+	}
+	if !exists {
+		return nil
+	}
+	oldChartDir, err := FindAppChartDir(chartsDir, o.AppName, o.Pack)
+	if err != nil {
+		return err
+	}
+	if oldChartDir == "" {
+		return nil
+	}
+
+	// chart expects folder name to be the same as app name
+	newChartDir := filepath.Join(chartsDir, o.AppName)
+	oldNames := []string{filepath.Base(oldChartDir)}
+	chartName, err := loadChartName(filepath.Join(oldChartDir, "Chart.yaml"))
+	if err != nil {
+		return err
+	}
+	if chartName != "" && stringhelpers.StringArrayIndex(oldNames, chartName) < 0 {
+		oldNames = append(oldNames, chartName)
+	}
+
+	if oldChartDir != newChartDir {
+		err = files.RenameDir(oldChartDir, newChartDir, false)
+		if err != nil {
+			return errors.Wrapf(err, "failed to rename %s to %s", oldChartDir, newChartDir)
+		}
+	}
+	return renameChart(chartsDir, oldNames, o.AppName)
+}
+
+// FindAppChartDir returns the application chart folder in the charts dir preferring the first of the given names
+// which exists otherwise the first chart which is not the preview chart
+func FindAppChartDir(chartsDir string, preferredNames ...string) (string, error) {
+	fileSlice, err := os.ReadDir(chartsDir)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read dir %s", chartsDir)
+	}
+	var names []string
+	for _, fi := range fileSlice {
+		name := fi.Name()
+		if fi.IsDir() && name != previewChartName && name != ".git" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+	sort.Strings(names)
+	for _, name := range preferredNames {
+		if name != "" && stringhelpers.StringArrayIndex(names, name) >= 0 {
+			return filepath.Join(chartsDir, name), nil
+		}
+	}
+	if len(names) > 1 {
+		log.Logger().Warnf("found charts %s but none named %s so using %s", strings.Join(names, ", "), strings.Join(preferredNames, " or "), names[0])
+	}
+	return filepath.Join(chartsDir, names[0]), nil
+}
+
+// renameChart updates the chart called newName in the charts dir so that it and the preview chart
+// no longer refer to any of the old chart names.
+//
+// This updates the name in the Chart.yaml, the top level keys, nameOverride and fullnameOverride in
+// the values.yaml and the dependencies of the preview chart
+func renameChart(chartsDir string, oldNames []string, newName string) error {
+	renamed := func(value string) (string, bool) {
+		if value != newName && stringhelpers.StringArrayIndex(oldNames, value) >= 0 {
+			return newName, true
+		}
+		return value, false
+	}
+
+	chartDir := filepath.Join(chartsDir, newName)
+	err := modifyYAMLFile(filepath.Join(chartDir, "Chart.yaml"), func(node *yaml.Node) bool {
+		return setMapValue(node, "name", newName)
+	})
+	if err != nil {
+		return err
+	}
+	err = modifyYAMLFile(filepath.Join(chartDir, "values.yaml"), func(node *yaml.Node) bool {
+		modified := renameMapKeys(node, renamed)
+		for _, key := range []string{"nameOverride", "fullnameOverride"} {
+			value := mapValue(node, key)
+			if value != nil && value.Kind == yaml.ScalarNode {
+				if name, ok := renamed(value.Value); ok {
+					value.Value = name
+					modified = true
+				}
+			}
+		}
+		return modified
+	})
+	if err != nil {
+		return err
+	}
+
+	previewDir := filepath.Join(chartsDir, previewChartName)
+	for _, name := range []string{"requirements.yaml", "Chart.yaml"} {
+		err = modifyYAMLFile(filepath.Join(previewDir, name), func(node *yaml.Node) bool {
+			return renameDependencies(node, oldNames, newName)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return modifyYAMLFile(filepath.Join(previewDir, "values.yaml"), func(node *yaml.Node) bool {
+		return renameMapKeys(node, renamed)
+	})
+}
+
+// renameDependencies updates the name and local repository of any dependencies on the old chart names
+func renameDependencies(node *yaml.Node, oldNames []string, newName string) bool {
+	deps := mapValue(node, "dependencies")
+	if deps == nil || deps.Kind != yaml.SequenceNode {
+		return false
+	}
+	modified := false
+	for _, dep := range deps.Content {
+		for _, oldName := range oldNames {
+			if oldName == newName {
+				continue
+			}
+			name := mapValue(dep, "name")
+			if name != nil && name.Value == oldName {
+				name.Value = newName
+				modified = true
+			}
+			repo := mapValue(dep, "repository")
+			if repo != nil && strings.TrimSuffix(repo.Value, "/") == "file://../"+oldName {
+				repo.Value = "file://../" + newName
+				modified = true
+			}
+		}
+	}
+	return modified
+}
+
+func loadChartName(path string) (string, error) {
+	name := ""
+	err := modifyYAMLFile(path, func(node *yaml.Node) bool {
+		value := mapValue(node, "name")
+		if value != nil {
+			name = value.Value
+		}
+		return false
+	})
+	return name, err
+}
+
+// modifyYAMLFile applies the function to the top level node of the YAML file if it exists
+// saving it if the function returns true
+func modifyYAMLFile(path string, fn func(node *yaml.Node) bool) error {
+	node, err := loadYAMLFile(path)
+	if err != nil || node == nil {
+		return err
+	}
+	if !fn(node.YNode()) {
+		return nil
+	}
+	err = yaml.WriteFile(node, path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save file %s", path)
+	}
+	return nil
+}
+
+// loadYAMLFile parses the YAML file returning nil if it does not exist or is empty
+func loadYAMLFile(path string) (*yaml.RNode, error) {
+	exists, err := files.FileExists(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check if file exists %s", path)
+	}
+	if !exists {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read file %s", path)
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return nil, nil
+	}
+	node, err := yaml.Parse(string(data))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse YAML file %s", path)
+	}
+	return node, nil
+}
+
+func mapValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setMapValue(node *yaml.Node, key, value string) bool {
+	v := mapValue(node, key)
+	if v == nil || v.Kind != yaml.ScalarNode || v.Value == value {
+		return false
+	}
+	v.Value = value
+	return true
+}
+
+func renameMapKeys(node *yaml.Node, renamed func(string) (string, bool)) bool {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return false
+	}
+	modified := false
+	for i := 0; i < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if name, ok := renamed(key.Value); ok && mapValue(node, name) == nil {
+			key.Value = name
+			modified = true
+		}
+	}
+	return modified
+}
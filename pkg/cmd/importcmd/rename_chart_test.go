@@ -0,0 +1,101 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameChartToMatchAppName(t *testing.T) {
+	dir := t.TempDir()
+	chartsDir := filepath.Join(dir, "charts")
+	writeTestFiles(t, chartsDir, map[string]string{
+		"another/Chart.yaml": "name: another\n",
+		"go/Chart.yaml": `apiVersion: v1
+description: A Helm chart for Kubernetes
+# the chart name
+name: go-chart
+version: 0.1.0
+maintainers:
+- name: go-chart
+`,
+		"go/values.yaml": `fullnameOverride: go-chart
+nameOverride: something
+go-chart:
+  enabled: true
+service:
+  name: go-chart
+`,
+		"preview/requirements.yaml": `dependencies:
+- alias: preview
+  name: go-chart
+  repository: file://../go
+- name: postgresql
+  repository: https://charts.bitnami.com/bitnami
+`,
+		"preview/values.yaml": `go:
+  image: foo
+preview:
+  name: go-chart
+`,
+	})
+
+	o := &importcmd.ImportOptions{}
+	o.Dir = dir
+	o.AppName = "myapp"
+	o.Pack = "go"
+	err := o.RenameChartToMatchAppName()
+	require.NoError(t, err, "failed to rename the chart")
+
+	assert.NoDirExists(t, filepath.Join(chartsDir, "go"))
+	assert.FileExists(t, filepath.Join(chartsDir, "another", "Chart.yaml"), "should only rename the chart named after the pack")
+
+	assertFileEquals(t, filepath.Join(chartsDir, "myapp", "Chart.yaml"), `apiVersion: v1
+description: A Helm chart for Kubernetes
+# the chart name
+name: myapp
+version: 0.1.0
+maintainers:
+- name: go-chart
+`)
+	assertFileEquals(t, filepath.Join(chartsDir, "myapp", "values.yaml"), `fullnameOverride: myapp
+nameOverride: something
+myapp:
+  enabled: true
+service:
+  name: go-chart
+`)
+	assertFileEquals(t, filepath.Join(chartsDir, "preview", "requirements.yaml"), `dependencies:
+- alias: preview
+  name: myapp
+  repository: file://../myapp
+- name: postgresql
+  repository: https://charts.bitnami.com/bitnami
+`)
+	assertFileEquals(t, filepath.Join(chartsDir, "preview", "values.yaml"), `myapp:
+  image: foo
+preview:
+  name: go-chart
+`)
+}
+
+func writeTestFiles(t *testing.T, dir string, fileMap map[string]string) {
+	for name, text := range fileMap {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(text), 0o600))
+	}
+}
+
+func assertFileEquals(t *testing.T, path, expected string) {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err, "failed to read %s", path)
+	assert.Equal(t, expected, string(data), "file %s", path)
+}
@@ -1,12 +1,15 @@
 package importcmd
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 
 	"github.com/jenkins-x-plugins/jx-gitops/pkg/cmd/repository/add"
 	"github.com/jenkins-x-plugins/jx-promote/pkg/environments"
+	"github.com/jenkins-x/go-scm/scm"
 	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/kube/jxenv"
 	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
 	"github.com/jenkins-x/jx-logging/v3/pkg/log"
@@ -34,12 +37,81 @@ func (o *ImportOptions) addSourceConfigPullRequest(gitURL, gitKind string) (bool
 		return remoteCluster, errors.Errorf("no git source URL for Environment %s", devEnv.Name)
 	}
 
+	var pr *scm.PullRequest
+	created := false
+	if o.isStepCompleted(StepDevPullRequest) {
+		remoteCluster = o.state.RemoteCluster
+		pr = o.resumedPullRequest()
+	} else {
+		pr, created, remoteCluster, err = o.createSourceConfigPullRequest(devGitURL, safeGitURL, gitKind)
+		if err != nil {
+			return remoteCluster, err
+		}
+		if o.state != nil {
+			o.state.RemoteCluster = remoteCluster
+			if pr != nil {
+				o.state.PullRequestURL = pr.Link
+				o.state.PullRequestNumber = pr.Number
+				o.state.PullRequestRepository = pr.Repository().FullName
+			}
+		}
+		err = o.completeStep(StepDevPullRequest)
+		if err != nil {
+			return remoteCluster, err
+		}
+	}
+	if pr == nil {
+		return remoteCluster, nil
+	}
+
+	prURL := pr.Link
+	if created {
+		o.GetReporter().CreatedDevRepoPullRequest(prURL, devGitURL)
+	} else {
+		o.GetReporter().ResumedDevRepoPullRequest(prURL, devGitURL)
+	}
+	if o.WaitForSourceRepositoryPullRequest && !o.isStepCompleted(StepWaitDevPullRequest) {
+
+		log.Logger().Info("")
+		log.Logger().Info("we now need to wait for the Pull Request to merge so that CI/CD can be setup via GitOps")
+		log.Logger().Info("")
+
+		err = o.waitForSourceRepositoryPullRequest(pr)
+		if err != nil {
+			return remoteCluster, errors.Wrapf(err, "failed to wait for the Pull Request %s to merge", prURL)
+		}
+		err = o.completeStep(StepWaitDevPullRequest)
+		if err != nil {
+			return remoteCluster, err
+		}
+	}
+	return remoteCluster, nil
+}
+
+// createSourceConfigPullRequest creates the Pull Request on the dev environment git repository to add the repository
+// returning whether the Pull Request was created rather than found from a previous import
+func (o *ImportOptions) createSourceConfigPullRequest(devGitURL, safeGitURL, gitKind string) (*scm.PullRequest, bool, bool, error) {
+	remoteCluster := false
+	commitTitle := fmt.Sprintf("chore: import repository %s", safeGitURL)
+	if o.Resume {
+		pr := o.findExistingPullRequest(devGitURL, commitTitle)
+		if pr != nil {
+			log.Logger().Infof("resuming import with the existing Pull Request %s", info(pr.Link))
+			// the Pull Request adds a remote cluster environment if the repository is a cluster git repository
+			gitops, err := IsRemoteClusterGitRepository(o.Dir)
+			if err != nil {
+				return pr, false, remoteCluster, errors.Wrapf(err, "failed to detect gitops repository for repo %s", safeGitURL)
+			}
+			return pr, false, gitops, nil
+		}
+	}
+
 	// let's generate a PR
 	if o.SchedulerName == "" {
 		g := filepath.Join(o.Dir, ".lighthouse", "*", "triggers.yaml")
 		matches, err := filepath.Glob(g)
 		if err != nil {
-			return remoteCluster, errors.Wrapf(err, "failed to evaluate glob %s", g)
+			return nil, false, remoteCluster, errors.Wrapf(err, "failed to evaluate glob %s", g)
 		}
 		if len(matches) > 0 {
 			o.SchedulerName = "in-repo"
@@ -54,7 +126,7 @@ func (o *ImportOptions) addSourceConfigPullRequest(gitURL, gitKind string) (bool
 		OutDir:            "",
 		BranchName:        "",
 		PullRequestNumber: 0,
-		CommitTitle:       fmt.Sprintf("chore: import repository %s", safeGitURL),
+		CommitTitle:       commitTitle,
 		CommitMessage:     "this commit will trigger a pipeline to [generate the CI/CD configuration](https://jenkins-x.io/v3/about/how-it-works/#importing--creating-quickstarts) which will create a second commit on this Pull Request before it auto merges",
 		ScmClient:         o.ScmFactory.ScmClient,
 		BatchMode:         o.BatchMode,
@@ -93,23 +165,66 @@ func (o *ImportOptions) addSourceConfigPullRequest(gitURL, gitKind string) (bool
 
 	pr, err := pro.Create(devGitURL, "", []string{"env/dev"}, false)
 	if err != nil {
-		return remoteCluster, errors.Wrapf(err, "failed to create Pull Request on the development environment git repository %s", devGitURL)
+		return nil, false, remoteCluster, errors.Wrapf(err, "failed to create Pull Request on the development environment git repository %s", devGitURL)
 	}
-	prURL := ""
-	if pr != nil {
-		prURL = pr.Link
-		if o.WaitForSourceRepositoryPullRequest {
+	return pr, pr != nil, remoteCluster, nil
+}
 
-			log.Logger().Info("")
-			log.Logger().Info("we now need to wait for the Pull Request to merge so that CI/CD can be setup via GitOps")
-			log.Logger().Info("")
+// resumedPullRequest returns the Pull Request created by the previous import
+func (o *ImportOptions) resumedPullRequest() *scm.PullRequest {
+	if o.state == nil || o.state.PullRequestNumber == 0 {
+		return nil
+	}
+	return &scm.PullRequest{
+		Number: o.state.PullRequestNumber,
+		Link:   o.state.PullRequestURL,
+		Base: scm.PullRequestBranch{
+			Repo: scm.Repository{
+				FullName: o.state.PullRequestRepository,
+			},
+		},
+	}
+}
 
-			err = o.waitForSourceRepositoryPullRequest(pr)
-			if err != nil {
-				return remoteCluster, errors.Wrapf(err, "failed to wait for the Pull Request %s to merge", prURL)
+// findExistingPullRequest finds the Pull Request from a previous import on the dev environment git repository
+func (o *ImportOptions) findExistingPullRequest(devGitURL, title string) *scm.PullRequest {
+	gitInfo, err := giturl.ParseGitURL(devGitURL)
+	if err != nil {
+		log.Logger().Debugf("failed to parse git URL %s: %s", devGitURL, err.Error())
+		return nil
+	}
+	fullName := scm.Join(gitInfo.Organisation, gitInfo.Name)
+	pr, err := FindPullRequestByTitle(o.ScmFactory.ScmClient, fullName, title)
+	if err != nil {
+		log.Logger().Debugf("failed to find the Pull Request on %s: %s", fullName, err.Error())
+		return nil
+	}
+	return pr
+}
+
+// FindPullRequestByTitle finds the open or merged Pull Request with the given title on the repository handling paging.
+// Pull Requests which were closed without merging are ignored
+func FindPullRequestByTitle(scmClient *scm.Client, fullName, title string) (*scm.PullRequest, error) {
+	ctx := context.Background()
+	opts := &scm.PullRequestListOptions{
+		Page:   1,
+		Size:   100,
+		Open:   true,
+		Closed: true,
+	}
+	for {
+		prs, _, err := scmClient.PullRequests.List(ctx, fullName, opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list Pull Requests on %s", fullName)
+		}
+		for _, pr := range prs {
+			if pr.Title == title && (!pr.Closed || pr.Merged) {
+				return pr, nil
 			}
 		}
+		if len(prs) < opts.Size {
+			return nil, nil
+		}
+		opts.Page++
 	}
-	o.GetReporter().CreatedDevRepoPullRequest(prURL, devGitURL)
-	return remoteCluster, nil
 }
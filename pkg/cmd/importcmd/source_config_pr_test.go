@@ -0,0 +1,70 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/jenkins-x/go-scm/scm"
+	fakescm "github.com/jenkins-x/go-scm/scm/driver/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindPullRequestByTitle(t *testing.T) {
+	client, data := fakescm.NewDefault()
+	fullName := "myorg/cluster"
+	addPullRequest := func(number int, title string, closed, merged bool) {
+		data.PullRequests[number] = &scm.PullRequest{
+			Number: number,
+			Title:  title,
+			Closed: closed,
+			Merged: merged,
+			Link:   fmt.Sprintf("https://github.com/%s/pull/%d", fullName, number),
+			Base: scm.PullRequestBranch{
+				Repo: scm.Repository{
+					FullName: fullName,
+				},
+			},
+		}
+	}
+	for i := 1; i <= 150; i++ {
+		addPullRequest(i, fmt.Sprintf("chore: some change %d", i), false, false)
+	}
+	addPullRequest(151, "chore: import repository https://github.com/myorg/closed", true, false)
+	addPullRequest(152, "chore: import repository https://github.com/myorg/merged", true, true)
+	addPullRequest(153, "chore: import repository https://github.com/myorg/open", false, false)
+
+	testCases := []struct {
+		title    string
+		expected int
+	}{
+		{
+			title:    "chore: import repository https://github.com/myorg/open",
+			expected: 153,
+		},
+		{
+			title:    "chore: import repository https://github.com/myorg/merged",
+			expected: 152,
+		},
+		{
+			title: "chore: import repository https://github.com/myorg/closed",
+		},
+		{
+			title: "chore: import repository https://github.com/myorg/missing",
+		},
+	}
+	for _, tc := range testCases {
+		pr, err := importcmd.FindPullRequestByTitle(client, fullName, tc.title)
+		require.NoError(t, err, "failed to find Pull Request %s", tc.title)
+		if tc.expected == 0 {
+			assert.Nil(t, pr, "should not find Pull Request %s", tc.title)
+			continue
+		}
+		require.NotNil(t, pr, "should find Pull Request %s", tc.title)
+		assert.Equal(t, tc.expected, pr.Number, "Pull Request number for %s", tc.title)
+	}
+}
@@ -0,0 +1,144 @@
+package importcmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/constants"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/yamls"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/jenkins-x/lighthouse-client/pkg/triggerconfig"
+	"github.com/jenkins-x/lighthouse-client/pkg/triggerconfig/inrepo"
+	"github.com/pkg/errors"
+)
+
+var placeholderRegex = regexp.MustCompile(constants.PlaceHolderPrefix + `_[A-Za-z0-9_]*`)
+
+// ValidateLighthouse validates the lighthouse triggers and pipelines in the .lighthouse folder of the given dir
+// returning a description of each problem found
+func ValidateLighthouse(dir string) ([]string, error) {
+	lighthouseDir := filepath.Join(dir, ".lighthouse")
+	exists, err := files.DirExists(lighthouseDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check if dir exists %s", lighthouseDir)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	var problems []string
+	g := filepath.Join(lighthouseDir, "*", "triggers.yaml")
+	triggerFiles, err := filepath.Glob(g)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to evaluate glob %s", g)
+	}
+	sort.Strings(triggerFiles)
+	for _, triggersFile := range triggerFiles {
+		problems = append(problems, validateTriggers(dir, triggersFile)...)
+	}
+
+	err = filepath.WalkDir(lighthouseDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read file %s", path)
+		}
+		tokens := placeholderRegex.FindAllString(string(data), -1)
+		if len(tokens) > 0 {
+			problems = append(problems, fmt.Sprintf("%s contains unresolved placeholders: %s", relativePath(dir, path), strings.Join(uniqueStrings(tokens), ", ")))
+		}
+		return nil
+	})
+	if err != nil {
+		return problems, errors.Wrapf(err, "failed to find placeholders in dir %s", lighthouseDir)
+	}
+	return problems, nil
+}
+
+// validateTriggers checks the triggers file parses and that each pipeline it references exists and can be converted to a PipelineRun
+func validateTriggers(dir, triggersFile string) []string {
+	name := relativePath(dir, triggersFile)
+	repoConfig := &triggerconfig.Config{}
+	err := yamls.LoadFile(triggersFile, repoConfig)
+	if err != nil {
+		return []string{fmt.Sprintf("%s could not be parsed: %s", name, err.Error())}
+	}
+
+	var problems []string
+	triggersDir := filepath.Dir(triggersFile)
+	validateSourcePath := func(kind, jobName, sourcePath string) {
+		if sourcePath == "" || strings.Contains(sourcePath, "://") {
+			return
+		}
+		path := filepath.Join(triggersDir, sourcePath)
+		exists, err := files.FileExists(path)
+		if err != nil || !exists {
+			problems = append(problems, fmt.Sprintf("%s %s %s has a sourcePath %s which does not exist", name, kind, jobName, sourcePath))
+			return
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s could not be read: %s", relativePath(dir, path), err.Error()))
+			return
+		}
+		_, err = inrepo.ConvertTektonResourceAsPipelineRun(data, "for file "+path, nil)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s is not a valid pipeline: %s", relativePath(dir, path), err.Error()))
+		}
+	}
+	for i := range repoConfig.Spec.Presubmits {
+		r := &repoConfig.Spec.Presubmits[i]
+		validateSourcePath("presubmit", r.Name, r.SourcePath)
+	}
+	for i := range repoConfig.Spec.Postsubmits {
+		r := &repoConfig.Spec.Postsubmits[i]
+		validateSourcePath("postsubmit", r.Name, r.SourcePath)
+	}
+	return problems
+}
+
+// validateLighthouse fails if the lighthouse configuration is invalid unless the validation is skipped
+func (o *ImportOptions) validateLighthouse() error {
+	if o.SkipValidation {
+		log.Logger().Warnf("skipping the validation of the lighthouse triggers and pipelines")
+		return nil
+	}
+	problems, err := ValidateLighthouse(o.Dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to validate the lighthouse configuration")
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	for _, p := range problems {
+		log.Logger().Errorf("%s", p)
+	}
+	return errors.Errorf("found %d problems in the lighthouse triggers and pipelines. Please fix them or use --skip-validation to push anyway", len(problems))
+}
+
+func relativePath(dir, path string) string {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+func uniqueStrings(values []string) []string {
+	var answer []string
+	for _, v := range values {
+		if stringhelpers.StringArrayIndex(answer, v) < 0 {
+			answer = append(answer, v)
+		}
+	}
+	return answer
+}
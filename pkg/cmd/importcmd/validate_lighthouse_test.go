@@ -0,0 +1,122 @@
+//go:build unit
+// +build unit
+
+package importcmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	validTriggers = `apiVersion: config.lighthouse.jenkins-x.io/v1alpha1
+kind: TriggerConfig
+spec:
+  presubmits:
+  - name: pr
+    context: "pr"
+    always_run: true
+    optional: false
+    source: "pullrequest.yaml"
+  postsubmits:
+  - name: release
+    context: "release"
+    source: "release.yaml"
+    branches:
+    - ^main$
+    - ^master$
+`
+
+	validPipeline = `apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: pullrequest
+spec:
+  pipelineSpec:
+    tasks:
+    - name: from-build-pack
+      taskSpec:
+        steps:
+        - name: build
+          image: golang:1.22
+          script: make build
+  serviceAccountName: tekton-bot
+`
+)
+
+func TestValidateLighthouse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		problems []string
+	}{
+		{
+			name: "valid",
+			files: map[string]string{
+				"triggers.yaml":    validTriggers,
+				"pullrequest.yaml": validPipeline,
+				"release.yaml":     validPipeline,
+			},
+		},
+		{
+			name: "bad-triggers",
+			files: map[string]string{
+				"triggers.yaml": "spec: [",
+			},
+			problems: []string{".lighthouse/jenkins-x/triggers.yaml could not be parsed"},
+		},
+		{
+			name: "missing-source",
+			files: map[string]string{
+				"triggers.yaml":    validTriggers,
+				"pullrequest.yaml": validPipeline,
+			},
+			problems: []string{".lighthouse/jenkins-x/triggers.yaml postsubmit release has a sourcePath release.yaml which does not exist"},
+		},
+		{
+			name: "bad-pipeline",
+			files: map[string]string{
+				"triggers.yaml":    validTriggers,
+				"pullrequest.yaml": validPipeline,
+				"release.yaml":     "apiVersion: tekton.dev/v1beta1\nkind: Cheese\n",
+			},
+			problems: []string{".lighthouse/jenkins-x/release.yaml is not a valid pipeline"},
+		},
+		{
+			name: "placeholders",
+			files: map[string]string{
+				"triggers.yaml":    validTriggers,
+				"pullrequest.yaml": validPipeline,
+				"release.yaml":     validPipeline + "# REPLACE_ME_APP_NAME REPLACE_ME_ORG REPLACE_ME_APP_NAME\n",
+			},
+			problems: []string{".lighthouse/jenkins-x/release.yaml contains unresolved placeholders: REPLACE_ME_APP_NAME, REPLACE_ME_ORG"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			pipelineDir := filepath.Join(dir, ".lighthouse", "jenkins-x")
+			require.NoError(t, os.MkdirAll(pipelineDir, 0o755))
+			for name, text := range tc.files {
+				require.NoError(t, os.WriteFile(filepath.Join(pipelineDir, name), []byte(text), 0o600))
+			}
+
+			problems, err := importcmd.ValidateLighthouse(dir)
+			require.NoError(t, err, "failed to validate")
+			require.Len(t, problems, len(tc.problems), "problems: %v", problems)
+			for i, p := range tc.problems {
+				assert.Contains(t, problems[i], p)
+			}
+		})
+	}
+
+	problems, err := importcmd.ValidateLighthouse(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, problems, "should ignore a dir without .lighthouse")
+}
@@ -0,0 +1,128 @@
+package importcmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/constants"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/stringhelpers"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/lint"
+	"helm.sh/helm/v3/pkg/lint/support"
+)
+
+// TeamChartValues returns the chart values for the deploy kind, canary and hpa settings of the team
+func TeamChartValues(deployKind string, deployOptions v1.DeployOptions) map[string]interface{} {
+	return map[string]interface{}{
+		"knativeDeploy": deployKind == constants.DeployKindKnative,
+		"canary": map[string]interface{}{
+			"enabled": deployOptions.Canary,
+		},
+		"hpa": map[string]interface{}{
+			"enabled": deployOptions.HPA,
+		},
+	}
+}
+
+// VerifyChart runs the helm lint rules on the chart dir and renders the templates with the default values
+// and then with the given team values. Errors are returned as problems and warnings are logged
+func VerifyChart(chartDir, namespace string, teamValues map[string]interface{}) ([]string, error) {
+	exists, err := files.FileExists(filepath.Join(chartDir, "Chart.yaml"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check if the chart exists in %s", chartDir)
+	}
+	if !exists {
+		return nil, errors.Errorf("there is no Chart.yaml in dir %s", chartDir)
+	}
+
+	ch, err := loader.Load(chartDir)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to load the chart: %s", err.Error())}, nil
+	}
+
+	var problems, warnings []string
+	valueSets := []struct {
+		name   string
+		values map[string]interface{}
+	}{
+		{name: "default values"},
+		{name: "team values", values: teamValues},
+	}
+	for _, vs := range valueSets {
+		linter := lint.All(chartDir, vs.values, namespace, false)
+		for _, m := range linter.Messages {
+			text := fmt.Sprintf("%s: %s", m.Path, m.Err.Error())
+			switch m.Severity {
+			case support.ErrorSev:
+				text = fmt.Sprintf("%s with %s", text, vs.name)
+				if stringhelpers.StringArrayIndex(problems, text) < 0 {
+					problems = append(problems, text)
+				}
+			case support.WarningSev:
+				if stringhelpers.StringArrayIndex(warnings, text) < 0 {
+					warnings = append(warnings, text)
+				}
+			}
+		}
+
+		// the lint rules do not fail on missing required values so lets render the chart too
+		err = renderChart(ch, namespace, vs.values)
+		if err != nil {
+			text := fmt.Sprintf("%s with %s", err.Error(), vs.name)
+			if stringhelpers.StringArrayIndex(problems, text) < 0 {
+				problems = append(problems, text)
+			}
+		}
+	}
+	for _, w := range warnings {
+		log.Logger().Warnf("%s", w)
+	}
+	return problems, nil
+}
+
+func renderChart(ch *chart.Chart, namespace string, values map[string]interface{}) error {
+	options := chartutil.ReleaseOptions{
+		Name:      ch.Name(),
+		Namespace: namespace,
+		IsInstall: true,
+	}
+	renderValues, err := chartutil.ToRenderValues(ch, values, options, chartutil.DefaultCapabilities)
+	if err != nil {
+		return err
+	}
+	_, err = engine.Render(ch, renderValues)
+	return err
+}
+
+// VerifyChart verifies the application chart renders with the default and team values
+func (o *ImportOptions) VerifyChart() error {
+	if o.SkipValidation {
+		return nil
+	}
+	chartDir := filepath.Join(o.Dir, ChartsDir, o.AppName)
+	exists, err := files.FileExists(filepath.Join(chartDir, "Chart.yaml"))
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if the chart exists in %s", chartDir)
+	}
+	if !exists {
+		return nil
+	}
+	problems, err := VerifyChart(chartDir, o.Namespace, TeamChartValues(o.DeployKind, o.DeployOptions))
+	if err != nil {
+		return errors.Wrapf(err, "failed to verify the chart %s", chartDir)
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	for _, p := range problems {
+		log.Logger().Errorf("%s", p)
+	}
+	return errors.Errorf("found %d problems in the chart %s. Please fix them or use --skip-validation to import anyway", len(problems), chartDir)
+}
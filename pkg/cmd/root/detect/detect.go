@@ -0,0 +1,243 @@
+package detect
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/input/inputfactory"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/options"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Options contains the command line options
+type Options struct {
+	importcmd.ImportOptions
+	Output string
+}
+
+// Result the result of detecting the pack for some source code
+type Result struct {
+	// Dir the directory of the source code
+	Dir string `json:"dir"`
+	// PacksDir the packs directory of the pipeline catalog
+	PacksDir string `json:"packsDir"`
+	// Languages the languages detected by linguist
+	Languages []importcmd.PlanLanguage `json:"languages,omitempty"`
+	// Candidates the candidate packs ordered by descending score
+	Candidates []*importcmd.PackCandidate `json:"candidates,omitempty"`
+	// Selected the pack which would be used by an import
+	Selected *importcmd.PackCandidate `json:"selected,omitempty"`
+}
+
+var (
+	cmdLong = templates.LongDesc(`
+		Detects which pipeline catalog pack would be used to import the source code without changing anything.
+
+		Displays the languages detected by linguist, every candidate pack with its score and the rule which matched and the pack which would be chosen.
+`)
+
+	cmdExample = templates.Examples(`
+		# Detects the pack for the current dir
+		jx project detect
+
+		# Detects the pack for a dir using a local pipeline catalog as JSON
+		jx project detect --dir src/myapp --pipeline-catalog-dir ../jx3-pipeline-catalog/packs -o json
+	`)
+)
+
+// NewCmdDetect creates the command
+func NewCmdDetect() (*cobra.Command, *Options) {
+	o := &Options{}
+
+	cmd := &cobra.Command{
+		Use:     "detect",
+		Short:   "Detects which pipeline catalog pack would be used to import the source code",
+		Long:    cmdLong,
+		Example: cmdExample,
+		Run: func(_ *cobra.Command, _ []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Dir, "dir", "", ".", "The directory of the source code")
+	cmd.Flags().StringVarP(&o.Pack, "pack", "", "", "The name of the pipeline catalog pack which would be specified on import")
+	cmd.Flags().StringVarP(&o.PipelineCatalogDir, "pipeline-catalog-dir", "", "", "The pipeline catalog packs directory to use instead of the pipeline catalog of the dev Environment")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", "text", "The output format which is either text or json")
+
+	o.AddBaseFlags(cmd)
+	return cmd, o
+}
+
+// Validate verifies settings
+func (o *Options) Validate() error {
+	err := o.BaseOptions.Validate()
+	if err != nil {
+		return errors.Wrapf(err, "failed to validate base options")
+	}
+	if o.Output != "text" && o.Output != "json" {
+		return options.InvalidOptionf("output", o.Output, "supported values are: text, json")
+	}
+	if o.Input == nil {
+		o.Input = inputfactory.NewInput(&o.BaseOptions)
+	}
+	if o.Dir == "" {
+		o.Dir = "."
+	}
+	o.Dir, err = filepath.Abs(o.Dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the absolute dir of %s", o.Dir)
+	}
+	if o.PipelineCatalogDir == "" {
+		err = o.LoadDevEnvironment()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run implements this command
+func (o *Options) Run() error {
+	err := o.Validate()
+	if err != nil {
+		return err
+	}
+	out := o.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	// lets keep the log output separate from the results so they can be parsed
+	log.SetOutput(os.Stderr)
+
+	result, err := o.Detect()
+	if err != nil {
+		return err
+	}
+	if o.Output == "json" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal result to JSON")
+		}
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	}
+	return WriteText(out, result)
+}
+
+// Detect detects the pack for the source code
+func (o *Options) Detect() (*Result, error) {
+	packsDir := o.PipelineCatalogDir
+	if packsDir == "" {
+		devEnvCloneDir, err := o.CloneDevEnvironment()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to clone dev env git repository")
+		}
+		defer os.RemoveAll(devEnvCloneDir) //nolint:errcheck
+
+		packsDir, _, err = o.InitBuildPacks(&importcmd.InvokeDraftPack{
+			Dir:            o.Dir,
+			DevEnvCloneDir: devEnvCloneDir,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to initialise the pipeline catalog")
+		}
+	}
+
+	c := &importcmd.DetectContext{
+		Dir:      o.Dir,
+		PacksDir: packsDir,
+		Out:      io.Discard,
+	}
+	result := &Result{
+		Dir:      o.Dir,
+		PacksDir: packsDir,
+	}
+	langs, err := c.Languages()
+	if err != nil {
+		log.Logger().Debugf("failed to detect languages in %s: %s", o.Dir, err.Error())
+	}
+	for _, l := range langs {
+		result.Languages = append(result.Languages, importcmd.PlanLanguage{Language: l.Language, Percent: l.Percent})
+	}
+
+	if o.Pack != "" && c.PackExists(o.Pack) {
+		result.Selected = &importcmd.PackCandidate{
+			Pack:   o.Pack,
+			Reason: "specified via --pack",
+		}
+	}
+	selected, candidates, err := importcmd.SelectPack(c)
+	result.Candidates = candidates
+	if result.Selected == nil {
+		if err != nil {
+			return nil, err
+		}
+		result.Selected = selected
+	}
+	// lets switch go libraries to the release only pack like an import would unless the pack was specified in batch mode
+	if result.Selected != nil && !(o.BatchMode && o.Pack != "") {
+		pack, err := importcmd.GoLibraryPack(o.Dir, packsDir, result.Selected.Pack)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to check for a go library")
+		}
+		if pack != result.Selected.Pack {
+			result.Selected = &importcmd.PackCandidate{
+				Pack:     pack,
+				Detector: result.Selected.Detector,
+				Reason:   fmt.Sprintf("the go module has no main package so the release only pack replaces %s", result.Selected.Pack),
+			}
+		}
+	}
+	return result, nil
+}
+
+// WriteText writes the result as text
+func WriteText(out io.Writer, result *Result) error {
+	info := termcolor.ColorInfo
+	fmt.Fprintf(out, "Source: %s\n", result.Dir)
+	fmt.Fprintf(out, "Pipeline catalog: %s\n\n", result.PacksDir)
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "LANGUAGE\tPERCENT")
+	for _, l := range result.Languages {
+		fmt.Fprintf(w, "%s\t%.1f%%\n", l.Language, l.Percent)
+	}
+	err := w.Flush()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out)
+
+	w = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PACK\tSCORE\tDETECTOR\tREASON")
+	for _, c := range result.Candidates {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", c.Pack, c.Score, c.Detector, c.Reason)
+	}
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out)
+
+	if result.Selected == nil {
+		fmt.Fprintln(out, "No pack would be selected")
+		return nil
+	}
+	from := result.Selected.Reason
+	if result.Selected.Detector != "" {
+		from = fmt.Sprintf("from %s: %s", result.Selected.Detector, result.Selected.Reason)
+	}
+	fmt.Fprintf(out, "Selected pack: %s %s\n", info(result.Selected.Pack), from)
+	return nil
+}
@@ -0,0 +1,77 @@
+//go:build unit
+// +build unit
+
+package detect_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/root/detect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	testData := filepath.Join("..", "..", "importcmd", "test_data", "detect")
+
+	_, o := detect.NewCmdDetect()
+	o.Dir = filepath.Join(testData, "source", "quarkus")
+	o.PipelineCatalogDir = filepath.Join(testData, "packs")
+	o.Output = "json"
+	o.BatchMode = true
+	buf := &bytes.Buffer{}
+	o.Out = buf
+
+	err := o.Run()
+	require.NoError(t, err, "failed to run detect")
+
+	result := &detect.Result{}
+	err = json.Unmarshal(buf.Bytes(), result)
+	require.NoError(t, err, "failed to parse result %s", buf.String())
+
+	require.NotNil(t, result.Selected, "result.Selected")
+	assert.Equal(t, "quarkus", result.Selected.Pack, "result.Selected.Pack")
+	assert.Equal(t, "Catalog", result.Selected.Detector, "result.Selected.Detector")
+
+	packs := map[string]string{}
+	for _, c := range result.Candidates {
+		packs[c.Detector] = c.Pack
+	}
+	assert.Equal(t, "quarkus", packs["PomFlavour"], "the quarkus candidate from the pom.xml should be included")
+
+	buf.Reset()
+	err = detect.WriteText(buf, result)
+	require.NoError(t, err, "failed to write text")
+	assert.Contains(t, buf.String(), "PomFlavour")
+	assert.Contains(t, buf.String(), "Selected pack:")
+}
+
+func TestDetectGoLibrary(t *testing.T) {
+	dir := t.TempDir()
+	packsDir := t.TempDir()
+	for path, text := range map[string]string{
+		filepath.Join(dir, "go.mod"):                                                      "module example.com/mylib\n\ngo 1.21\n",
+		filepath.Join(dir, "lib.go"):                                                      "package mylib\n",
+		filepath.Join(packsDir, "go", "charts", "go", "Chart.yaml"):                       "name: go\n",
+		filepath.Join(packsDir, "go-library", ".lighthouse", "jenkins-x", "release.yaml"): "kind: PipelineRun\n",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755), "failed to create dir for %s", path)
+		require.NoError(t, os.WriteFile(path, []byte(text), 0o600), "failed to write %s", path)
+	}
+
+	_, o := detect.NewCmdDetect()
+	o.Dir = dir
+	o.PipelineCatalogDir = packsDir
+	o.Pack = "go"
+	o.Output = "json"
+	o.Out = &bytes.Buffer{}
+
+	result, err := o.Detect()
+	require.NoError(t, err, "failed to detect")
+	require.NotNil(t, result.Selected, "result.Selected")
+	assert.Equal(t, "go-library", result.Selected.Pack, "result.Selected.Pack")
+}
@@ -68,6 +68,9 @@ type CreatePullRequestOptions struct {
 	Push      bool
 	Fork      bool
 
+	// CreateBranch creates a new branch for the pull request rather than using the current branch
+	CreateBranch bool
+
 	Input   input.Interface
 	Results *scm.PullRequest
 }
@@ -221,7 +224,7 @@ func (o *CreatePullRequestOptions) createPullRequestDetails(po *environments.Env
 	if title == "" {
 		return fmt.Errorf("no title specified")
 	}
-	if po.BranchName == "" {
+	if po.BranchName == "" && !o.CreateBranch {
 		var err error
 		po.BranchName, err = gitclient.Branch(o.GitClient, o.Dir)
 		if err != nil {
@@ -173,30 +173,24 @@ func (o *CreateQuickstartOptions) CreateQuickStart(q *quickstarts.QuickstartForm
 
 	}
 
-	/* TODO
 	githubAppMode, err := o.IsGitHubAppMode()
 	if err != nil {
 		return err
 	}
-
 	if githubAppMode {
-		githubApp := &github.GithubApp{
-			Factory: o.GetFactory(),
-		}
-
-		owner := o.GitRepositoryOptions.Owner
-		repoName := o.GitRepositoryOptions.RepoName
+		owner := o.GitRepositoryOptions.Namespace
+		repoName := q.Name
 		if details != nil {
 			owner = details.Organisation
 			repoName = details.RepoName
 		}
-		installed, err := githubApp.Install(owner, repoName, o.GetIOFileHandles(), true)
-		if err != nil {
-			return err
+		if owner != "" && repoName != "" {
+			_, err = o.EnsureGithubAppInstalled(owner, repoName, true)
+			if err != nil {
+				return err
+			}
 		}
-		o.GithubAppInstalled = installed
 	}
-	*/
 
 	currentUser, err := o.ScmFactory.GetUsername()
 	if err != nil {
@@ -220,6 +214,11 @@ func (o *CreateQuickstartOptions) CreateQuickStart(q *quickstarts.QuickstartForm
 	if err != nil {
 		return err
 	}
+	// any go template configuration comes from the quickstart so all of its templates are rendered
+	o.AddedGoTemplateConfig, err = files.FileExists(filepath.Join(genDir, ".jx", importcmd.GoTemplateFileName))
+	if err != nil {
+		return errors.Wrapf(err, "failed to check for the go template configuration of the quickstart")
+	}
 
 	// if there is a charts folder named after the app name, lets rename it to the generated app name
 	folder := ""
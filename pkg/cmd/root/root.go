@@ -6,7 +6,10 @@ import (
 
 	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/root/version"
 
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/root/detect"
 	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/root/enable"
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/root/upgrade"
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/root/verifychart"
 
 	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/common"
 	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
@@ -81,12 +84,16 @@ func NewCmdMain() (*cobra.Command, *WizardOptions) {
 		},
 	}
 
+	cmd.AddCommand(cobras.SplitCommand(detect.NewCmdDetect()))
 	cmd.AddCommand(cobras.SplitCommand(enable.NewCmdPipelineEnable()))
 	cmd.AddCommand(cobras.SplitCommand(NewCmdCreateQuickstart()))
 	cmd.AddCommand(cobras.SplitCommand(NewCmdCreateMLQuickstart()))
 	cmd.AddCommand(NewCmdCreateSpring())
 	cmd.AddCommand(importcmd.NewCmdImport())
 	cmd.AddCommand(pullrequest.NewCmdCreatePullRequest())
+	cmd.AddCommand(cobras.SplitCommand(upgrade.NewCmdUpgradePipelines()))
+	cmd.AddCommand(cobras.SplitCommand(upgrade.NewCmdConvertToUses()))
+	cmd.AddCommand(cobras.SplitCommand(verifychart.NewCmdVerifyChart()))
 	cmd.AddCommand(version.NewCmdVersion())
 
 	return cmd, options
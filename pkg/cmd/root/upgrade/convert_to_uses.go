@@ -0,0 +1,408 @@
+package upgrade
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// StatusConverted the pipeline was converted to inherit its steps via uses:
+	StatusConverted = "converted"
+
+	defaultConvertTitle = "chore: use the pipeline catalog via uses"
+)
+
+var (
+	// stepOverrides the step properties lighthouse can override when a step is inherited via uses:
+	stepOverrides = map[string]bool{
+		"args": true, "command": true, "computeResources": true, "env": true, "envFrom": true, "imagePullPolicy": true,
+		"resources": true, "script": true, "timeout": true, "volumeMounts": true, "workingDir": true,
+	}
+
+	// taskSpecOverrides the task spec properties lighthouse merges with the inherited task
+	taskSpecOverrides = map[string]bool{
+		"params": true, "results": true, "sidecars": true, "volumes": true, "workspaces": true,
+	}
+
+	convertLong = templates.LongDesc(`
+		Converts the pipelines copied into the .lighthouse folders into PipelineRuns which inherit their steps from the pipeline catalog via uses: at a pinned version.
+
+		Steps which have been changed locally are kept as overrides or as local steps if lighthouse cannot override the changed properties. The Kptfile of each converted folder is removed as the pipelines are no longer copied.
+`)
+
+	convertExample = templates.Examples(`
+		# Converts the pipelines in the current dir using the commit of their Kptfile
+		jx project convert-to-uses
+
+		# Converts the pipelines to use a release of the pipeline catalog and creates a Pull Request
+		jx project convert-to-uses --version v1.2.3 --pr
+	`)
+)
+
+// ConvertOptions contains the command line options
+type ConvertOptions struct {
+	Options
+
+	// Version the version of the pipeline catalog to use which defaults to the commit of the Kptfile
+	Version string
+}
+
+// stepCounts the number of steps of a converted pipeline by how they are inherited
+type stepCounts struct {
+	inherited  int
+	overridden int
+	local      int
+}
+
+// NewCmdConvertToUses creates the command
+func NewCmdConvertToUses() (*cobra.Command, *ConvertOptions) {
+	o := &ConvertOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "convert-to-uses",
+		Short:   "Converts the pipelines in the .lighthouse folders to inherit their steps from the pipeline catalog via uses:",
+		Long:    convertLong,
+		Example: convertExample,
+		Run: func(_ *cobra.Command, _ []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Version, "version", "", "", "The git tag, branch or sha of the pipeline catalog to use. Defaults to the commit of the Kptfile")
+	o.addFlags(cmd, defaultConvertTitle)
+	return cmd, o
+}
+
+// Run implements this command
+func (o *ConvertOptions) Run() error {
+	err := o.Validate()
+	if err != nil {
+		return err
+	}
+	defer o.removeClones()
+
+	kptfiles, err := importcmd.FindLighthouseKptfiles(o.Dir)
+	if err != nil {
+		return err
+	}
+	if len(kptfiles) == 0 {
+		log.Logger().Infof("no Kptfile files found in the .lighthouse folders of %s", o.Dir)
+		return nil
+	}
+	for _, path := range kptfiles {
+		err = o.convertPipeline(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = o.writeResults()
+	if err != nil {
+		return err
+	}
+	if o.PullRequest {
+		return o.createPullRequest()
+	}
+	return nil
+}
+
+// convertPipeline converts the PipelineRuns of the folder of the Kptfile to uses: and removes the Kptfile
+func (o *ConvertOptions) convertPipeline(kptfilePath string) error {
+	kf, err := importcmd.LoadKptfile(kptfilePath)
+	if err != nil {
+		return err
+	}
+	pipelineDir := filepath.Dir(kptfilePath)
+	name := filepath.Base(pipelineDir)
+	upstream := kf.GitUpstream()
+	if upstream == nil || upstream.Commit == "" {
+		log.Logger().Warnf("ignoring %s as it has no upstream git repository and commit", kptfilePath)
+		return nil
+	}
+	usesPrefix, err := usesRepository(upstream.Repo)
+	if err != nil {
+		return err
+	}
+	version := o.Version
+	if version == "" {
+		version = upstream.Commit
+	}
+
+	cloneDir, err := o.clone(upstream.UpstreamGitURL())
+	if err != nil {
+		return err
+	}
+	srcDir := upstream.UpstreamDirectory()
+	upstreamFiles, err := o.readUpstreamFiles(cloneDir, upstream.Commit, srcDir)
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(pipelineDir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read dir %s", pipelineDir)
+	}
+	converted := false
+	for _, f := range entries {
+		fileName := f.Name()
+		ext := filepath.Ext(fileName)
+		if f.IsDir() || (ext != ".yaml" && ext != ".yml") || fileName == "triggers.yaml" {
+			continue
+		}
+		upstreamData := upstreamFiles[fileName]
+		if len(upstreamData) == 0 {
+			log.Logger().Debugf("ignoring %s as it is not in the pipeline catalog", fileName)
+			continue
+		}
+		path := filepath.Join(pipelineDir, fileName)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read file %s", path)
+		}
+		sourceURI := usesPrefix + "/" + strings.TrimPrefix(srcDir+"/"+fileName, "/") + "@" + version
+		result, counts, err := convertToUses(data, upstreamData, sourceURI)
+		if err != nil {
+			return errors.Wrapf(err, "failed to convert %s", path)
+		}
+		if result == nil {
+			continue
+		}
+		err = os.WriteFile(path, result, files.DefaultFileWritePermissions)
+		if err != nil {
+			return errors.Wrapf(err, "failed to save file %s", path)
+		}
+		converted = true
+		o.Results = append(o.Results, &FileResult{
+			Pipeline: name,
+			File:     fileName,
+			Status:   StatusConverted,
+			Message:  fmt.Sprintf("%d inherited, %d overridden and %d local steps", counts.inherited, counts.overridden, counts.local),
+		})
+	}
+	if !converted {
+		return nil
+	}
+
+	err = os.Remove(kptfilePath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove %s", kptfilePath)
+	}
+	o.Results = append(o.Results, &FileResult{
+		Pipeline: name,
+		File:     importcmd.KptfileName,
+		Status:   StatusRemoved,
+	})
+	log.Logger().Infof("converted pipeline %s to use %s", termcolor.ColorInfo(name), termcolor.ColorInfo(usesPrefix+"@"+version))
+	return nil
+}
+
+// usesRepository returns the owner/repository of the git URL for uses: with the lighthouse: prefix for git servers
+// other than github
+func usesRepository(gitURL string) (string, error) {
+	gitInfo, err := giturl.ParseGitURL(gitURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse git URL %s", gitURL)
+	}
+	answer := gitInfo.Organisation + "/" + gitInfo.Name
+	if gitInfo.Host != "github.com" {
+		answer = "lighthouse:" + answer
+	}
+	return answer, nil
+}
+
+// convertToUses converts the local PipelineRun into one which inherits the steps of the upstream PipelineRun via the
+// uses: source URI keeping the local changes. Returns nil if the file is not a PipelineRun which can be converted
+func convertToUses(data, upstreamData []byte, sourceURI string) ([]byte, *stepCounts, error) {
+	local := map[string]interface{}{}
+	err := yaml.Unmarshal(data, &local)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to parse YAML")
+	}
+	upstream := map[string]interface{}{}
+	err = yaml.Unmarshal(upstreamData, &upstream)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to parse upstream YAML")
+	}
+	if local["kind"] != "PipelineRun" || upstream["kind"] != "PipelineRun" {
+		return nil, nil, nil
+	}
+	localSpec := nestedMap(local, "spec", "pipelineSpec")
+	upstreamSpec := nestedMap(upstream, "spec", "pipelineSpec")
+	if localSpec == nil || upstreamSpec == nil {
+		return nil, nil, nil
+	}
+
+	counts := &stepCounts{}
+	converted := false
+	for _, key := range []string{"tasks", "finally"} {
+		localTasks, _ := localSpec[key].([]interface{})
+		upstreamTasks, _ := upstreamSpec[key].([]interface{})
+		for _, lt := range localTasks {
+			localTask, ok := lt.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			upstreamTask := findByName(upstreamTasks, localTask["name"])
+			if upstreamTask == nil && len(localTasks) == 1 && len(upstreamTasks) == 1 {
+				upstreamTask, _ = upstreamTasks[0].(map[string]interface{})
+			}
+			if upstreamTask == nil {
+				continue
+			}
+			if convertTask(localTask, upstreamTask, sourceURI, counts) {
+				converted = true
+			}
+		}
+	}
+	if !converted {
+		return nil, nil, nil
+	}
+	result, err := yaml.Marshal(local)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to marshal YAML")
+	}
+	return result, counts, nil
+}
+
+// convertTask replaces the task spec with the uses: step template and the steps which differ from the upstream task
+func convertTask(localTask, upstreamTask map[string]interface{}, sourceURI string, counts *stepCounts) bool {
+	taskName := localTask["name"]
+	ts, _ := localTask["taskSpec"].(map[string]interface{})
+	upstreamTS, _ := upstreamTask["taskSpec"].(map[string]interface{})
+	if ts == nil || upstreamTS == nil {
+		return false
+	}
+	localTemplate, _ := ts["stepTemplate"].(map[string]interface{})
+	if image, _ := localTemplate["image"].(string); strings.HasPrefix(image, "uses:") {
+		return false
+	}
+
+	answer := map[string]interface{}{}
+	for k, v := range ts {
+		if k == "steps" || k == "stepTemplate" || reflect.DeepEqual(v, upstreamTS[k]) {
+			continue
+		}
+		if !taskSpecOverrides[k] {
+			log.Logger().Warnf("task %v has local changes to %s which cannot be kept when using uses:", taskName, k)
+			continue
+		}
+		answer[k] = v
+	}
+
+	upstreamTemplate, _ := upstreamTS["stepTemplate"].(map[string]interface{})
+	stepTemplate := map[string]interface{}{}
+	for k, v := range localTemplate {
+		if k == "name" || k == "image" || reflect.DeepEqual(v, upstreamTemplate[k]) {
+			continue
+		}
+		if !stepOverrides[k] && k != "securityContext" {
+			log.Logger().Warnf("task %v has local changes to stepTemplate.%s which cannot be kept when using uses:", taskName, k)
+			continue
+		}
+		stepTemplate[k] = v
+	}
+	stepTemplate["image"] = "uses:" + sourceURI
+	answer["stepTemplate"] = stepTemplate
+
+	templateImage, _ := localTemplate["image"].(string)
+	if templateImage == "" {
+		templateImage, _ = upstreamTemplate["image"].(string)
+	}
+	localSteps, _ := ts["steps"].([]interface{})
+	upstreamSteps, _ := upstreamTS["steps"].([]interface{})
+	var steps []interface{}
+	for _, s := range localSteps {
+		step, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		upstreamStep := findByName(upstreamSteps, step["name"])
+		override, inherit := stepOverride(step, upstreamStep)
+		switch {
+		case !inherit:
+			// lets keep the whole step and make sure it does not default to the uses: image
+			if _, ok := step["image"]; !ok && templateImage != "" {
+				step["image"] = templateImage
+			}
+			steps = append(steps, step)
+			counts.local++
+		case len(override) > 1:
+			steps = append(steps, override)
+			counts.overridden++
+		default:
+			steps = append(steps, override)
+			counts.inherited++
+		}
+	}
+	answer["steps"] = steps
+	localTask["taskSpec"] = answer
+	return true
+}
+
+// stepOverride returns the step which inherits the upstream step with the local changes as overrides or false if the
+// step has changes lighthouse cannot override so it must be kept as a local step
+func stepOverride(step, upstreamStep map[string]interface{}) (map[string]interface{}, bool) {
+	if upstreamStep == nil {
+		return nil, false
+	}
+	answer := map[string]interface{}{
+		"name": step["name"],
+	}
+	for k, v := range step {
+		if k == "name" || reflect.DeepEqual(v, upstreamStep[k]) {
+			continue
+		}
+		// lighthouse only uses a securityContext if the inherited step has none
+		if !stepOverrides[k] && !(k == "securityContext" && upstreamStep[k] == nil) {
+			return nil, false
+		}
+		answer[k] = v
+	}
+	for k, v := range upstreamStep {
+		if _, ok := step[k]; !ok && v != nil {
+			// a property was removed locally which cannot be expressed as an override
+			return nil, false
+		}
+	}
+	return answer, true
+}
+
+// findByName returns the map in the list with the given name
+func findByName(list []interface{}, name interface{}) map[string]interface{} {
+	if name == nil || name == "" {
+		return nil
+	}
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if ok && m["name"] == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// nestedMap returns the map at the path of keys or nil
+func nestedMap(m map[string]interface{}, keys ...string) map[string]interface{} {
+	for _, k := range keys {
+		m, _ = m[k].(map[string]interface{})
+		if m == nil {
+			return nil
+		}
+	}
+	return m
+}
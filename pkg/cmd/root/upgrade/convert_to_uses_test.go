@@ -0,0 +1,134 @@
+//go:build unit
+// +build unit
+
+package upgrade_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/root/upgrade"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	catalogPipeline = `apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: pullrequest
+spec:
+  pipelineSpec:
+    tasks:
+    - name: from-build-pack
+      taskSpec:
+        stepTemplate:
+          image: golang:1.22
+          workingDir: /workspace/source
+        steps:
+        - name: git-clone
+          image: jx-git:1.0
+          script: git clone
+        - name: build
+          script: make build
+        - name: lint
+          script: make lint
+  serviceAccountName: tekton-bot
+`
+
+	localPipeline = `apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: pullrequest
+spec:
+  pipelineSpec:
+    tasks:
+    - name: from-build-pack
+      taskSpec:
+        stepTemplate:
+          image: golang:1.22
+          workingDir: /workspace/source
+        steps:
+        - name: git-clone
+          image: jx-git:1.0
+          script: git clone
+        - name: build
+          script: make build-all
+        - name: lint
+          image: golangci-lint:1.60
+          script: make lint
+        - name: my-step
+          script: make my-step
+  serviceAccountName: tekton-bot
+`
+)
+
+func TestConvertToUses(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	catalogDir := t.TempDir()
+	packDir := filepath.Join(catalogDir, "packs", "go", ".lighthouse", "jenkins-x")
+	git(t, catalogDir, "init", "-q", "-b", "master")
+	writeFiles(t, packDir, map[string]string{
+		"triggers.yaml":    triggers,
+		"pullrequest.yaml": catalogPipeline,
+	})
+	git(t, catalogDir, "add", "-A")
+	git(t, catalogDir, "commit", "-q", "-m", "initial")
+	sha := strings.TrimSpace(git(t, catalogDir, "rev-parse", "HEAD"))
+
+	// lets clone the github catalog from the local repository
+	catalogURL := "https://github.com/jenkins-x/jx3-pipeline-catalog"
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "url."+catalogDir+".insteadOf")
+	t.Setenv("GIT_CONFIG_VALUE_0", catalogURL+".git")
+
+	dir := t.TempDir()
+	pipelineDir := filepath.Join(dir, ".lighthouse", "jenkins-x")
+	writeFiles(t, pipelineDir, map[string]string{
+		"triggers.yaml":    triggers,
+		"pullrequest.yaml": localPipeline,
+	})
+	kf := importcmd.NewKptfile("jenkins-x", catalogURL, "/packs/go/.lighthouse/jenkins-x", "master", sha)
+	require.NoError(t, importcmd.SaveKptfile(kf, filepath.Join(pipelineDir, importcmd.KptfileName)))
+
+	_, o := upgrade.NewCmdConvertToUses()
+	o.Dir = dir
+	o.Version = "v1.2.3"
+	o.Out = &bytes.Buffer{}
+
+	err := o.Run()
+	require.NoError(t, err, "failed to convert to uses")
+
+	assert.NoFileExists(t, filepath.Join(pipelineDir, importcmd.KptfileName))
+	require.Len(t, o.Results, 2)
+	assert.Equal(t, upgrade.StatusConverted, o.Results[0].Status)
+	assert.Equal(t, "1 inherited, 1 overridden and 2 local steps", o.Results[0].Message)
+	assert.Equal(t, upgrade.StatusRemoved, o.Results[1].Status)
+
+	data, err := os.ReadFile(filepath.Join(pipelineDir, "pullrequest.yaml"))
+	require.NoError(t, err)
+	pr := map[string]interface{}{}
+	require.NoError(t, yaml.Unmarshal(data, &pr))
+	assert.Equal(t, "tekton-bot", pr["spec"].(map[string]interface{})["serviceAccountName"])
+
+	task := pr["spec"].(map[string]interface{})["pipelineSpec"].(map[string]interface{})["tasks"].([]interface{})[0].(map[string]interface{})
+	ts := task["taskSpec"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"image": "uses:jenkins-x/jx3-pipeline-catalog/packs/go/.lighthouse/jenkins-x/pullrequest.yaml@v1.2.3",
+	}, ts["stepTemplate"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "git-clone"},
+		map[string]interface{}{"name": "build", "script": "make build-all"},
+		map[string]interface{}{"name": "lint", "image": "golangci-lint:1.60", "script": "make lint"},
+		map[string]interface{}{"name": "my-step", "image": "golang:1.22", "script": "make my-step"},
+	}, ts["steps"])
+}
@@ -0,0 +1,596 @@
+package upgrade
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/root/pullrequest"
+	"github.com/jenkins-x-plugins/jx-project/pkg/constants"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cmdrunner"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/files"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/cli"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/gitdiscovery"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/gitclient/giturl"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// StatusUnchanged the file did not need changing
+	StatusUnchanged = "unchanged"
+	// StatusUpdated the file was replaced with the latest version as it had no local changes
+	StatusUpdated = "updated"
+	// StatusMerged the upstream changes were merged with the local changes
+	StatusMerged = "merged"
+	// StatusAdded the file was added upstream
+	StatusAdded = "added"
+	// StatusRemoved the file was removed upstream
+	StatusRemoved = "removed"
+	// StatusConflict the upstream changes conflict with the local changes
+	StatusConflict = "conflict"
+	// StatusMigrated the deprecated v1alpha1 Kptfile was migrated to kpt v1
+	StatusMigrated = "migrated"
+
+	defaultTitle = "chore: upgrade pipelines"
+)
+
+// Options contains the command line options
+type Options struct {
+	Dir         string
+	PullRequest bool
+	Title       string
+	Body        string
+	Labels      []string
+	BatchMode   bool
+
+	CommandRunner cmdrunner.CommandRunner
+	Gitter        gitclient.Interface
+	Out           io.Writer
+	Results       []*FileResult
+
+	// clones the upstream repository clone directories indexed by git URL
+	clones map[string]string
+	// replacer replaces the placeholders of the upstream files as they were replaced when the project was imported
+	replacer *strings.Replacer
+	// defaultTitle the Pull Request title of the command used if no title is specified
+	defaultTitle string
+}
+
+// FileResult the result of upgrading a file of a pipeline folder
+type FileResult struct {
+	// Pipeline the name of the .lighthouse folder
+	Pipeline string
+	// File the path of the file relative to the pipeline folder
+	File string
+	// Status the status of the upgrade
+	Status string
+	// Message an optional description of the status
+	Message string
+}
+
+var (
+	cmdLong = templates.LongDesc(`
+		Upgrades the pipelines in the .lighthouse folders to the latest version of the pipeline catalog.
+
+		The Kptfile of each folder records the pipeline catalog repository, directory and commit the pipelines were copied from. A three-way merge from that commit to the latest commit is used so that local changes are kept. Conflicts are reported per file and left in the file with conflict markers.
+`)
+
+	cmdExample = templates.Examples(`
+		# Upgrades the pipelines in the current dir
+		jx project upgrade-pipelines
+
+		# Upgrades the pipelines and creates a Pull Request
+		jx project upgrade-pipelines --pr
+	`)
+)
+
+// NewCmdUpgradePipelines creates the command
+func NewCmdUpgradePipelines() (*cobra.Command, *Options) {
+	o := &Options{}
+
+	cmd := &cobra.Command{
+		Use:     "upgrade-pipelines",
+		Short:   "Upgrades the pipelines in the .lighthouse folders to the latest version of the pipeline catalog",
+		Long:    cmdLong,
+		Example: cmdExample,
+		Run: func(_ *cobra.Command, _ []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+
+	o.addFlags(cmd, defaultTitle)
+	return cmd, o
+}
+
+func (o *Options) addFlags(cmd *cobra.Command, title string) {
+	o.defaultTitle = title
+	cmd.Flags().StringVarP(&o.Dir, "dir", "", ".", "The directory of the source code")
+	cmd.Flags().BoolVarP(&o.PullRequest, "pr", "", false, "Creates a Pull Request with the changed pipelines")
+	cmd.Flags().StringVarP(&o.Title, "title", "t", title, "The title of the Pull Request")
+	cmd.Flags().StringVarP(&o.Body, "body", "", "", "The body of the Pull Request")
+	cmd.Flags().StringArrayVarP(&o.Labels, "label", "l", []string{}, "The labels to add to the Pull Request")
+	cmd.Flags().BoolVarP(&o.BatchMode, "batch-mode", "b", false, "Enables batch mode which avoids prompting for user input")
+}
+
+// Validate verifies settings
+func (o *Options) Validate() error {
+	if o.CommandRunner == nil {
+		o.CommandRunner = cmdrunner.QuietCommandRunner
+	}
+	if o.Gitter == nil {
+		o.Gitter = cli.NewCLIClient("", o.CommandRunner)
+	}
+	if o.Out == nil {
+		o.Out = os.Stdout
+	}
+	if o.Dir == "" {
+		o.Dir = "."
+	}
+	var err error
+	o.Dir, err = filepath.Abs(o.Dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the absolute dir of %s", o.Dir)
+	}
+	if o.Title == "" {
+		o.Title = o.defaultTitle
+		if o.Title == "" {
+			o.Title = defaultTitle
+		}
+	}
+	return nil
+}
+
+// Run implements this command
+func (o *Options) Run() error {
+	err := o.Validate()
+	if err != nil {
+		return err
+	}
+	defer o.removeClones()
+
+	kptfiles, err := importcmd.FindLighthouseKptfiles(o.Dir)
+	if err != nil {
+		return err
+	}
+	if len(kptfiles) == 0 {
+		log.Logger().Infof("no Kptfile files found in the .lighthouse folders of %s", o.Dir)
+		return nil
+	}
+	o.replacer, err = o.placeholderReplacer()
+	if err != nil {
+		return err
+	}
+	for _, path := range kptfiles {
+		err = o.upgradePipeline(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = o.writeResults()
+	if err != nil {
+		return err
+	}
+
+	conflicts := o.Conflicts()
+	if len(conflicts) > 0 {
+		return errors.Errorf("%d files have conflicts which need resolving: %s", len(conflicts), strings.Join(conflicts, ", "))
+	}
+	if o.PullRequest {
+		return o.createPullRequest()
+	}
+	return nil
+}
+
+// Conflicts returns the paths relative to the .lighthouse folder of the files with conflicts
+func (o *Options) Conflicts() []string {
+	var answer []string
+	for _, r := range o.Results {
+		if r.Status == StatusConflict {
+			answer = append(answer, r.Pipeline+"/"+r.File)
+		}
+	}
+	return answer
+}
+
+// upgradePipeline merges the upstream changes since the commit of the Kptfile into its pipeline folder
+func (o *Options) upgradePipeline(kptfilePath string) error {
+	kf, err := importcmd.LoadKptfile(kptfilePath)
+	if err != nil {
+		return err
+	}
+	pipelineDir := filepath.Dir(kptfilePath)
+	name := filepath.Base(pipelineDir)
+	upstream := kf.GitUpstream()
+	if upstream == nil || upstream.Commit == "" {
+		log.Logger().Warnf("ignoring %s as it has no upstream git repository and commit", kptfilePath)
+		return nil
+	}
+	ref := upstream.Ref
+	if ref == "" {
+		ref = "master"
+	}
+
+	cloneDir, err := o.clone(upstream.UpstreamGitURL())
+	if err != nil {
+		return err
+	}
+	latest, err := o.resolveRef(cloneDir, ref)
+	if err != nil {
+		return err
+	}
+	if latest == upstream.Commit {
+		log.Logger().Infof("pipeline %s is up to date with %s", termcolor.ColorInfo(name), termcolor.ColorInfo(latest))
+		if !o.migrateKptfile(kf, name) {
+			return nil
+		}
+		return importcmd.SaveKptfile(kf, kptfilePath)
+	}
+
+	srcDir := upstream.UpstreamDirectory()
+	base, err := o.readUpstreamFiles(cloneDir, upstream.Commit, srcDir)
+	if err != nil {
+		return err
+	}
+	head, err := o.readUpstreamFiles(cloneDir, latest, srcDir)
+	if err != nil {
+		return err
+	}
+	o.replacePlaceholders(base)
+	o.replacePlaceholders(head)
+
+	var paths []string
+	for k := range base {
+		paths = append(paths, k)
+	}
+	for k := range head {
+		if _, ok := base[k]; !ok {
+			paths = append(paths, k)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, rel := range paths {
+		if rel == importcmd.KptfileName {
+			continue
+		}
+		r, err := o.upgradeFile(pipelineDir, rel, base, head)
+		if err != nil {
+			return errors.Wrapf(err, "failed to upgrade file %s of pipeline %s", rel, name)
+		}
+		if r != nil {
+			r.Pipeline = name
+			o.Results = append(o.Results, r)
+		}
+	}
+
+	o.migrateKptfile(kf, name)
+	kf.SetGitCommit(latest)
+	err = importcmd.SaveKptfile(kf, kptfilePath)
+	if err != nil {
+		return err
+	}
+	log.Logger().Infof("upgraded pipeline %s to %s", termcolor.ColorInfo(name), termcolor.ColorInfo(latest))
+	return nil
+}
+
+// placeholderReplacer returns the replacer of the placeholders using the git repository and project configuration of
+// the source code. Placeholders without a value are left unresolved
+func (o *Options) placeholderReplacer() (*strings.Replacer, error) {
+	importOptions := &importcmd.ImportOptions{}
+	importOptions.Dir = o.Dir
+	importOptions.Gitter = o.Gitter
+	config, err := importOptions.LoadProjectConfig(o.Dir)
+	if err != nil {
+		return nil, err
+	}
+	err = importOptions.ApplyProjectConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	if importOptions.DockerRegistry == "" {
+		importOptions.DockerRegistry = os.Getenv("DOCKER_REGISTRY")
+	}
+
+	gitServerName := ""
+	gitURL, err := gitdiscovery.FindGitURLFromDir(o.Dir, true)
+	if err != nil || gitURL == "" {
+		log.Logger().Debugf("could not find the git URL of %s so only placeholders from the project configuration are replaced", o.Dir)
+	} else {
+		gitInfo, err := giturl.ParseGitURL(gitURL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse git URL %s", gitURL)
+		}
+		gitServerName = gitInfo.Host
+		if importOptions.Organisation == "" {
+			importOptions.Organisation = gitInfo.Organisation
+		}
+		if importOptions.Repository == "" {
+			importOptions.Repository = gitInfo.Name
+		}
+	}
+	importOptions.AppName = importOptions.Repository
+	dockerRegistryOrg := importOptions.DockerRegistryOrg
+	if dockerRegistryOrg == "" {
+		dockerRegistryOrg = importOptions.Organisation
+	}
+
+	values := importOptions.PlaceholderValues(gitServerName, dockerRegistryOrg)
+	for k, v := range values {
+		if v == "" {
+			delete(values, k)
+		}
+	}
+	return importcmd.NewPlaceholderReplacer(values), nil
+}
+
+// replacePlaceholders replaces the placeholders in the upstream files so they can be compared with the local files
+func (o *Options) replacePlaceholders(upstreamFiles map[string][]byte) {
+	if o.replacer == nil {
+		return
+	}
+	for k, data := range upstreamFiles {
+		if bytes.Contains(data, []byte(constants.PlaceHolderPrefix)) {
+			upstreamFiles[k] = []byte(o.replacer.Replace(string(data)))
+		}
+	}
+}
+
+// migrateKptfile migrates a deprecated v1alpha1 Kptfile to kpt v1 returning true if it was migrated
+func (o *Options) migrateKptfile(kf *importcmd.Kptfile, name string) bool {
+	if !kf.Migrate() {
+		return false
+	}
+	o.Results = append(o.Results, &FileResult{
+		Pipeline: name,
+		File:     importcmd.KptfileName,
+		Status:   StatusMigrated,
+		Message:  "migrated to " + importcmd.KptAPIVersion,
+	})
+	return true
+}
+
+// upgradeFile performs the three-way merge of a file returning nil if the file is unchanged
+func (o *Options) upgradeFile(pipelineDir, rel string, base, head map[string][]byte) (*FileResult, error) {
+	path := filepath.Join(pipelineDir, filepath.FromSlash(rel))
+	baseData, inBase := base[rel]
+	headData, inHead := head[rel]
+	if inBase && inHead && bytes.Equal(baseData, headData) {
+		return nil, nil
+	}
+
+	exists, err := files.FileExists(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check if file exists %s", path)
+	}
+	var localData []byte
+	if exists {
+		localData, err = os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read file %s", path)
+		}
+	}
+	result := &FileResult{File: rel}
+
+	switch {
+	case !inHead:
+		// removed upstream
+		if !exists {
+			return nil, nil
+		}
+		if !bytes.Equal(localData, baseData) {
+			result.Status = StatusConflict
+			result.Message = "modified locally but removed upstream"
+			return result, nil
+		}
+		err = os.Remove(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to remove file %s", path)
+		}
+		result.Status = StatusRemoved
+		return result, nil
+
+	case !exists:
+		if inBase {
+			result.Status = StatusConflict
+			result.Message = "removed locally but modified upstream"
+			return result, nil
+		}
+		err = os.MkdirAll(filepath.Dir(path), files.DefaultDirWritePermissions)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create dir %s", filepath.Dir(path))
+		}
+		err = os.WriteFile(path, headData, files.DefaultFileWritePermissions)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to save file %s", path)
+		}
+		result.Status = StatusAdded
+		return result, nil
+
+	case bytes.Equal(localData, headData):
+		return nil, nil
+
+	case inBase && bytes.Equal(localData, baseData):
+		err = os.WriteFile(path, headData, files.DefaultFileWritePermissions)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to save file %s", path)
+		}
+		result.Status = StatusUpdated
+		return result, nil
+	}
+
+	conflict, err := o.mergeFile(path, baseData, headData)
+	if err != nil {
+		return nil, err
+	}
+	result.Status = StatusMerged
+	if conflict {
+		result.Status = StatusConflict
+		result.Message = "local and upstream changes overlap"
+	}
+	return result, nil
+}
+
+// mergeFile merges the changes from the base to the upstream version into the local file returning true if there are
+// conflicts which are left in the file with conflict markers
+func (o *Options) mergeFile(path string, baseData, headData []byte) (bool, error) {
+	tmpDir, err := os.MkdirTemp("", "jx-upgrade-pipelines-")
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(tmpDir) //nolint:errcheck
+
+	basePath := filepath.Join(tmpDir, "base")
+	headPath := filepath.Join(tmpDir, "upstream")
+	err = os.WriteFile(basePath, baseData, files.DefaultFileWritePermissions)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to save file %s", basePath)
+	}
+	err = os.WriteFile(headPath, headData, files.DefaultFileWritePermissions)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to save file %s", headPath)
+	}
+
+	// git merge-file returns the number of conflicts as the exit code
+	_, err = o.Gitter.Command(tmpDir, "merge-file", "-L", "local", "-L", "base", "-L", "upstream", path, basePath, headPath)
+	if err != nil {
+		exitErr, ok := errors.Cause(err).(*exec.ExitError)
+		if ok && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128 {
+			return true, nil
+		}
+		return false, errors.Wrapf(err, "failed to merge file %s", path)
+	}
+	return false, nil
+}
+
+// clone clones the upstream git repository once
+func (o *Options) clone(gitURL string) (string, error) {
+	if o.clones == nil {
+		o.clones = map[string]string{}
+	}
+	dir := o.clones[gitURL]
+	if dir != "" {
+		return dir, nil
+	}
+	dir, err := gitclient.CloneToDir(o.Gitter, gitURL, "")
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to clone %s", gitURL)
+	}
+	o.clones[gitURL] = dir
+	return dir, nil
+}
+
+func (o *Options) removeClones() {
+	for _, dir := range o.clones {
+		err := os.RemoveAll(dir)
+		if err != nil {
+			log.Logger().Warnf("failed to remove %s: %s", dir, err.Error())
+		}
+	}
+	o.clones = nil
+}
+
+// resolveRef returns the commit sha of the remote branch or tag
+func (o *Options) resolveRef(dir, ref string) (string, error) {
+	for _, r := range []string{"origin/" + ref, ref} {
+		sha, err := o.Gitter.Command(dir, "rev-parse", "--verify", r+"^{commit}")
+		if err == nil {
+			return strings.TrimSpace(sha), nil
+		}
+	}
+	return "", errors.Errorf("failed to find the ref %s in the upstream repository", ref)
+}
+
+// readUpstreamFiles reads the files of the directory at the commit indexed by their slash separated relative path
+func (o *Options) readUpstreamFiles(dir, sha, srcDir string) (map[string][]byte, error) {
+	_, err := o.Gitter.Command(dir, "checkout", "--force", "--detach", sha)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to checkout %s of the upstream repository", sha)
+	}
+	answer := map[string][]byte{}
+	root := filepath.Join(dir, filepath.FromSlash(srcDir))
+	exists, err := files.DirExists(root)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check if dir exists %s", root)
+	}
+	if !exists {
+		return answer, nil
+	}
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read file %s", path)
+		}
+		answer[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the files of %s at %s", srcDir, sha)
+	}
+	return answer, nil
+}
+
+// writeResults writes the changed files and their status
+func (o *Options) writeResults() error {
+	if len(o.Results) == 0 {
+		_, err := fmt.Fprintln(o.Out, "No pipeline files changed")
+		return err
+	}
+	w := tabwriter.NewWriter(o.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PIPELINE\tFILE\tSTATUS\tMESSAGE")
+	for _, r := range o.Results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Pipeline, r.File, r.Status, r.Message)
+	}
+	return w.Flush()
+}
+
+// createPullRequest creates a Pull Request with the changed pipelines
+func (o *Options) createPullRequest() error {
+	changes, err := gitclient.HasChanges(o.Gitter, o.Dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check for git changes in %s", o.Dir)
+	}
+	if !changes {
+		log.Logger().Infof("no changes so not creating a Pull Request")
+		return nil
+	}
+	po := &pullrequest.CreatePullRequestOptions{
+		BatchMode:    o.BatchMode,
+		Title:        o.Title,
+		Body:         o.Body,
+		Labels:       o.Labels,
+		Push:         true,
+		CreateBranch: true,
+	}
+	po.Dir = o.Dir
+	po.DiscoverFromGit = true
+	po.CommandRunner = o.CommandRunner
+	po.GitClient = o.Gitter
+	err = po.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to create Pull Request")
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+//go:build unit
+// +build unit
+
+package upgrade_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/root/upgrade"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	pipelineV1      = "steps:\n- name: build\n  image: golang:1.21\n- name: test\n  image: golang:1.21\n- name: lint\n  image: golangci-lint:1.50\n- name: release\n  image: jx-release:1.0\n"
+	pipelineV2      = "steps:\n- name: build\n  image: golang:1.22\n- name: test\n  image: golang:1.22\n- name: lint\n  image: golangci-lint:1.50\n- name: release\n  image: jx-release:1.0\n"
+	pipelineV1Local = "steps:\n- name: build\n  image: golang:1.21\n- name: test\n  image: golang:1.21\n- name: lint\n  image: golangci-lint:1.50\n- name: release\n  image: my-release:2.0\n"
+	releaseV1       = "image: jx-release:1.0\n"
+	releaseV2       = "image: jx-release:2.0\n"
+	releaseLocal    = "image: my-release:3.0\n"
+	triggers        = "kind: TriggerConfig\n"
+)
+
+func TestUpgradePipelines(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	catalogDir := t.TempDir()
+	packDir := filepath.Join(catalogDir, "packs", "go", ".lighthouse", "jenkins-x")
+	git(t, catalogDir, "init", "-q", "-b", "master")
+	writeFiles(t, packDir, map[string]string{
+		"triggers.yaml":    triggers,
+		"pullrequest.yaml": pipelineV1,
+		"release.yaml":     releaseV1,
+		"removed.yaml":     triggers,
+	})
+	git(t, catalogDir, "add", "-A")
+	git(t, catalogDir, "commit", "-q", "-m", "initial")
+	baseSha := strings.TrimSpace(git(t, catalogDir, "rev-parse", "HEAD"))
+
+	writeFiles(t, packDir, map[string]string{
+		"pullrequest.yaml": pipelineV2,
+		"release.yaml":     releaseV2,
+		"added.yaml":       triggers,
+	})
+	require.NoError(t, os.Remove(filepath.Join(packDir, "removed.yaml")))
+	git(t, catalogDir, "add", "-A")
+	git(t, catalogDir, "commit", "-q", "-m", "upgrade")
+	latestSha := strings.TrimSpace(git(t, catalogDir, "rev-parse", "HEAD"))
+
+	dir := t.TempDir()
+	pipelineDir := filepath.Join(dir, ".lighthouse", "jenkins-x")
+	writeFiles(t, pipelineDir, map[string]string{
+		"triggers.yaml":    triggers,
+		"pullrequest.yaml": pipelineV1Local,
+		"release.yaml":     releaseLocal,
+		"removed.yaml":     triggers,
+		"Kptfile": fmt.Sprintf("apiVersion: kpt.dev/v1alpha1\nkind: Kptfile\nmetadata:\n  name: jenkins-x\nupstream:\n  type: git\n  git:\n    commit: %s\n    repo: %s\n    directory: /packs/go/.lighthouse/jenkins-x\n    ref: master\n",
+			baseSha, catalogDir),
+	})
+
+	_, o := upgrade.NewCmdUpgradePipelines()
+	o.Dir = dir
+	buf := &bytes.Buffer{}
+	o.Out = buf
+
+	err := o.Run()
+	require.Error(t, err, "should report the conflict")
+	assert.Equal(t, []string{"jenkins-x/release.yaml"}, o.Conflicts())
+
+	status := map[string]string{}
+	for _, r := range o.Results {
+		status[r.File] = r.Status
+	}
+	assert.Equal(t, map[string]string{
+		"added.yaml":       upgrade.StatusAdded,
+		"pullrequest.yaml": upgrade.StatusMerged,
+		"release.yaml":     upgrade.StatusConflict,
+		"removed.yaml":     upgrade.StatusRemoved,
+		"Kptfile":          upgrade.StatusMigrated,
+	}, status)
+	assert.Contains(t, buf.String(), "release.yaml")
+
+	data, err := os.ReadFile(filepath.Join(pipelineDir, "pullrequest.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "golang:1.22", "should include the upstream changes")
+	assert.Contains(t, string(data), "my-release:2.0", "should keep the local changes")
+
+	data, err = os.ReadFile(filepath.Join(pipelineDir, "release.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "<<<<<<< local")
+
+	assert.FileExists(t, filepath.Join(pipelineDir, "added.yaml"))
+	assert.NoFileExists(t, filepath.Join(pipelineDir, "removed.yaml"))
+
+	kf, err := importcmd.LoadKptfile(filepath.Join(pipelineDir, importcmd.KptfileName))
+	require.NoError(t, err)
+	require.NotNil(t, kf.GitUpstream())
+	assert.Equal(t, latestSha, kf.GitUpstream().Commit, "the Kptfile commit should be upgraded")
+	assert.Equal(t, importcmd.KptAPIVersion, kf.APIVersion, "the Kptfile should be migrated")
+
+	// lets check running again does nothing now we are up to date
+	_, o = upgrade.NewCmdUpgradePipelines()
+	o.Dir = dir
+	o.Out = buf
+	err = o.Run()
+	require.NoError(t, err)
+	assert.Empty(t, o.Results)
+}
+
+func TestUpgradePipelinesMigratesUpToDateKptfile(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	catalogDir := t.TempDir()
+	packDir := filepath.Join(catalogDir, "packs", "go", ".lighthouse", "jenkins-x")
+	git(t, catalogDir, "init", "-q", "-b", "master")
+	writeFiles(t, packDir, map[string]string{
+		"triggers.yaml": triggers,
+		"release.yaml":  releaseV1,
+	})
+	git(t, catalogDir, "add", "-A")
+	git(t, catalogDir, "commit", "-q", "-m", "initial")
+	latestSha := strings.TrimSpace(git(t, catalogDir, "rev-parse", "HEAD"))
+
+	dir := t.TempDir()
+	pipelineDir := filepath.Join(dir, ".lighthouse", "jenkins-x")
+	writeFiles(t, pipelineDir, map[string]string{
+		"triggers.yaml": triggers,
+		"release.yaml":  releaseLocal,
+		"Kptfile": fmt.Sprintf("apiVersion: kpt.dev/v1alpha1\nkind: Kptfile\nmetadata:\n  name: jenkins-x\nupstream:\n  type: git\n  git:\n    commit: %s\n    repo: %s\n    directory: /packs/go/.lighthouse/jenkins-x\n    ref: master\n",
+			latestSha, catalogDir),
+	})
+
+	_, o := upgrade.NewCmdUpgradePipelines()
+	o.Dir = dir
+	o.Out = &bytes.Buffer{}
+	err := o.Run()
+	require.NoError(t, err)
+	require.Len(t, o.Results, 1)
+	assert.Equal(t, importcmd.KptfileName, o.Results[0].File)
+	assert.Equal(t, upgrade.StatusMigrated, o.Results[0].Status)
+
+	kf, err := importcmd.LoadKptfile(filepath.Join(pipelineDir, importcmd.KptfileName))
+	require.NoError(t, err)
+	require.NotNil(t, kf.GitUpstream())
+	assert.Equal(t, importcmd.KptAPIVersion, kf.APIVersion, "the Kptfile should be migrated")
+	assert.Equal(t, latestSha, kf.GitUpstream().Commit, "the Kptfile commit should be kept")
+
+	data, err := os.ReadFile(filepath.Join(pipelineDir, "release.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, releaseLocal, string(data), "should not change the pipeline files")
+}
+
+func TestUpgradePipelinesWithPlaceholders(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	const (
+		releaseTemplateV1 = "steps:\n- name: build\n  image: golang:1.21\n  script: make REPLACE_ME_APP_NAME\n- name: release\n  image: jx-release:1.0\n  script: jx promote REPLACE_ME_ORG/REPLACE_ME_APP_NAME\n"
+		releaseTemplateV2 = "steps:\n- name: build\n  image: golang:1.22\n  script: make REPLACE_ME_APP_NAME\n- name: release\n  image: jx-release:1.0\n  script: jx promote REPLACE_ME_ORG/REPLACE_ME_APP_NAME\n"
+		releaseImported   = "steps:\n- name: build\n  image: golang:1.21\n  script: make myapp\n- name: release\n  image: jx-release:1.0\n  script: jx promote myorg/myapp\n"
+		releaseUpgraded   = "steps:\n- name: build\n  image: golang:1.22\n  script: make myapp\n- name: release\n  image: jx-release:1.0\n  script: jx promote myorg/myapp\n"
+	)
+
+	catalogDir := t.TempDir()
+	packDir := filepath.Join(catalogDir, "packs", "go", ".lighthouse", "jenkins-x")
+	git(t, catalogDir, "init", "-q", "-b", "master")
+	writeFiles(t, packDir, map[string]string{
+		"triggers.yaml": triggers,
+		"release.yaml":  releaseTemplateV1,
+	})
+	git(t, catalogDir, "add", "-A")
+	git(t, catalogDir, "commit", "-q", "-m", "initial")
+	baseSha := strings.TrimSpace(git(t, catalogDir, "rev-parse", "HEAD"))
+
+	writeFiles(t, packDir, map[string]string{
+		"release.yaml": releaseTemplateV2,
+	})
+	git(t, catalogDir, "add", "-A")
+	git(t, catalogDir, "commit", "-q", "-m", "upgrade")
+
+	dir := t.TempDir()
+	git(t, dir, "init", "-q", "-b", "master")
+	git(t, dir, "remote", "add", "origin", "https://github.com/myorg/myapp.git")
+	pipelineDir := filepath.Join(dir, ".lighthouse", "jenkins-x")
+	writeFiles(t, pipelineDir, map[string]string{
+		"triggers.yaml": triggers,
+		"release.yaml":  releaseImported,
+		"Kptfile": fmt.Sprintf("apiVersion: kpt.dev/v1\nkind: Kptfile\nmetadata:\n  name: jenkins-x\nupstream:\n  type: git\n  git:\n    repo: %s\n    directory: /packs/go/.lighthouse/jenkins-x\n    ref: master\nupstreamLock:\n  type: git\n  git:\n    repo: %s\n    directory: /packs/go/.lighthouse/jenkins-x\n    ref: master\n    commit: %s\n",
+			catalogDir, catalogDir, baseSha),
+	})
+
+	_, o := upgrade.NewCmdUpgradePipelines()
+	o.Dir = dir
+	o.Out = &bytes.Buffer{}
+	err := o.Run()
+	require.NoError(t, err, "should upgrade the templated pipeline without conflicts")
+
+	status := map[string]string{}
+	for _, r := range o.Results {
+		status[r.File] = r.Status
+	}
+	assert.Equal(t, map[string]string{"release.yaml": upgrade.StatusUpdated}, status)
+
+	data, err := os.ReadFile(filepath.Join(pipelineDir, "release.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, releaseUpgraded, string(data), "should write the upstream changes with the placeholders replaced")
+}
+
+func TestDefaultPullRequestTitle(t *testing.T) {
+	_, o := upgrade.NewCmdUpgradePipelines()
+	o.Title = ""
+	require.NoError(t, o.Validate())
+	assert.Equal(t, "chore: upgrade pipelines", o.Title, "upgrade-pipelines title")
+
+	_, co := upgrade.NewCmdConvertToUses()
+	co.Title = ""
+	require.NoError(t, co.Validate())
+	assert.Equal(t, "chore: use the pipeline catalog via uses", co.Title, "convert-to-uses title")
+
+	o = &upgrade.Options{}
+	require.NoError(t, o.Validate())
+	assert.Equal(t, "chore: upgrade pipelines", o.Title, "title without flags")
+}
+
+func git(t *testing.T, dir string, args ...string) string {
+	c := exec.Command("git", args...)
+	c.Dir = dir
+	out, err := c.CombinedOutput()
+	require.NoError(t, err, "failed to run git %s: %s", strings.Join(args, " "), string(out))
+	return string(out)
+}
+
+func writeFiles(t *testing.T, dir string, m map[string]string) {
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	for name, text := range m {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(text), 0o600))
+	}
+}
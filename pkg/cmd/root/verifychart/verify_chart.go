@@ -0,0 +1,192 @@
+package verifychart
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/importcmd"
+	"github.com/jenkins-x-plugins/jx-project/pkg/constants"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx-api/v4/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/helper"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/cobras/templates"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube/jxclient"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube/jxenv"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/options"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/termcolor"
+	"github.com/jenkins-x/jx-logging/v3/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Options contains the command line options
+type Options struct {
+	options.BaseOptions
+	Dir            string
+	AppName        string
+	Chart          string
+	Namespace      string
+	DeployKind     string
+	DeployOptions  v1.DeployOptions
+	TeamSettings   bool
+	SkipValidation bool
+	Problems       []string
+
+	KubeClient kubernetes.Interface
+	JXClient   versioned.Interface
+	Cmd        *cobra.Command
+}
+
+var (
+	cmdLong = templates.LongDesc(`
+		Verifies the helm chart of a project by running the helm lint rules and rendering its templates.
+
+		The templates are rendered with the default values of the chart and then with the knative, canary and hpa values of the team.
+`)
+
+	cmdExample = templates.Examples(`
+		# Verifies the chart in the charts folder of the current dir
+		jx project verify-chart
+
+		# Verifies a chart rendering it for canary releases with the Horizontal Pod Autoscaler
+		jx project verify-chart --chart charts/myapp --canary --hpa
+
+		# Verifies a chart using the deploy kind of the team settings of the dev Environment
+		jx project verify-chart --team-settings
+
+		# Reports the problems of the chart of an application without failing
+		jx project verify-chart --app myapp --skip-validation
+	`)
+)
+
+// NewCmdVerifyChart creates the command
+func NewCmdVerifyChart() (*cobra.Command, *Options) {
+	o := &Options{}
+
+	cmd := &cobra.Command{
+		Use:     "verify-chart",
+		Short:   "Verifies the helm chart of a project by linting and rendering it",
+		Long:    cmdLong,
+		Example: cmdExample,
+		Run: func(_ *cobra.Command, _ []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+
+	o.Cmd = cmd
+	cmd.Flags().StringVarP(&o.Dir, "dir", "", ".", "The directory of the project")
+	cmd.Flags().StringVarP(&o.AppName, "app", "", "", "The name of the application used to find its chart in the charts folder. Defaults to the name of the directory")
+	cmd.Flags().StringVarP(&o.Chart, "chart", "c", "", "The chart directory to verify. Defaults to the chart in the charts folder which is not the preview chart")
+	cmd.Flags().StringVarP(&o.Namespace, "namespace", "n", "", "The namespace used to render the chart")
+	cmd.Flags().StringVarP(&o.DeployKind, "deploy-kind", "", "", fmt.Sprintf("The kind of deployment to render the chart for. Should be one of %s, %s", constants.DeployKindKnative, constants.DeployKindDefault))
+	cmd.Flags().BoolVarP(&o.DeployOptions.Canary, constants.OptionCanary, "", false, "Renders the chart for canary rollouts")
+	cmd.Flags().BoolVarP(&o.DeployOptions.HPA, constants.OptionHPA, "", false, "Renders the chart with the Horizontal Pod Autoscaler enabled")
+	cmd.Flags().BoolVarP(&o.TeamSettings, "team-settings", "", false, "Defaults the deploy kind from the team settings of the dev Environment")
+	cmd.Flags().BoolVarP(&o.SkipValidation, "skip-validation", "", false, "Reports the problems found in the chart without failing")
+
+	o.AddBaseFlags(cmd)
+	return cmd, o
+}
+
+// Validate verifies settings
+func (o *Options) Validate() error {
+	if o.Dir == "" {
+		o.Dir = "."
+	}
+	if o.Chart == "" {
+		chartsDir := filepath.Join(o.Dir, importcmd.ChartsDir)
+		dir, err := filepath.Abs(o.Dir)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find the absolute dir of %s", o.Dir)
+		}
+		o.Chart, err = importcmd.FindAppChartDir(chartsDir, o.AppName, filepath.Base(dir))
+		if err != nil {
+			return errors.Wrapf(err, "failed to find the chart in %s", chartsDir)
+		}
+		if o.Chart == "" {
+			return errors.Errorf("there is no chart in %s. Please specify one via --chart", chartsDir)
+		}
+	}
+	if o.TeamSettings {
+		err := o.DefaultsFromTeamSettings()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DefaultsFromTeamSettings defaults the deploy kind and options from the team settings of the dev Environment
+func (o *Options) DefaultsFromTeamSettings() error {
+	var err error
+	o.KubeClient, o.Namespace, err = kube.LazyCreateKubeClientAndNamespace(o.KubeClient, o.Namespace)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the kube client")
+	}
+	o.JXClient, err = jxclient.LazyCreateJXClient(o.JXClient)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the jx client")
+	}
+	settings, err := jxenv.GetDevEnvTeamSettings(o.JXClient, o.Namespace)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load Team Settings")
+	}
+	return o.DefaultValuesFromTeamSettings(settings)
+}
+
+// DefaultValuesFromTeamSettings defaults the deploy kind and any deploy options not specified via flags
+func (o *Options) DefaultValuesFromTeamSettings(settings *v1.TeamSettings) error {
+	if o.DeployKind == "" {
+		o.DeployKind = settings.DeployKind
+	}
+	teamDeployOptions := settings.GetDeployOptions()
+	if !o.flagChanged(constants.OptionCanary) {
+		o.DeployOptions.Canary = teamDeployOptions.Canary
+	}
+	if !o.flagChanged(constants.OptionHPA) {
+		o.DeployOptions.HPA = teamDeployOptions.HPA
+	}
+	return nil
+}
+
+// flagChanged returns true if the flag was specified on the command line
+func (o *Options) flagChanged(name string) bool {
+	if o.Cmd == nil {
+		return false
+	}
+	f := o.Cmd.Flags().Lookup(name)
+	return f != nil && f.Changed
+}
+
+// Run implements this command
+func (o *Options) Run() error {
+	err := o.Validate()
+	if err != nil {
+		return err
+	}
+	out := o.Out
+	if out == nil {
+		out = os.Stdout
+	}
+
+	o.Problems, err = importcmd.VerifyChart(o.Chart, o.Namespace, importcmd.TeamChartValues(o.DeployKind, o.DeployOptions))
+	if err != nil {
+		return errors.Wrapf(err, "failed to verify the chart %s", o.Chart)
+	}
+	if len(o.Problems) > 0 {
+		for _, p := range o.Problems {
+			fmt.Fprintf(out, "%s %s\n", termcolor.ColorError("error"), p)
+		}
+		if o.SkipValidation {
+			log.Logger().Warnf("ignoring %d problems in the chart %s as validation is skipped", len(o.Problems), termcolor.ColorInfo(o.Chart))
+			return nil
+		}
+		return errors.Errorf("found %d problems in the chart %s", len(o.Problems), o.Chart)
+	}
+	log.Logger().Infof("the chart %s is valid", termcolor.ColorInfo(o.Chart))
+	return nil
+}
@@ -0,0 +1,103 @@
+//go:build unit
+// +build unit
+
+package verifychart_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-plugins/jx-project/pkg/cmd/root/verifychart"
+	v1 "github.com/jenkins-x/jx-api/v4/pkg/apis/jenkins.io/v1"
+	fakejx "github.com/jenkins-x/jx-api/v4/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx-helpers/v3/pkg/kube/jxenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestVerifyChart(t *testing.T) {
+	testCases := []struct {
+		name           string
+		hpa            bool
+		skipValidation bool
+		problems       []string
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:     "hpa",
+			hpa:      true,
+			problems: []string{"hpa.maxReplicas is required"},
+		},
+		{
+			name:           "skip-validation",
+			hpa:            true,
+			skipValidation: true,
+			problems:       []string{"hpa.maxReplicas is required"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, o := verifychart.NewCmdVerifyChart()
+			o.Dir = filepath.Join("test_data", "myapp")
+			o.Namespace = "jx"
+			o.DeployOptions.HPA = tc.hpa
+			o.SkipValidation = tc.skipValidation
+			o.Out = &bytes.Buffer{}
+
+			err := o.Run()
+			assert.Equal(t, filepath.Join("test_data", "myapp", "charts", "myapp"), o.Chart)
+			require.Len(t, o.Problems, len(tc.problems), "problems: %v", o.Problems)
+			if len(tc.problems) == 0 || tc.skipValidation {
+				require.NoError(t, err, "failed to verify the chart")
+				return
+			}
+			require.Error(t, err, "should have failed to verify the chart")
+			for i, p := range tc.problems {
+				assert.Contains(t, o.Problems[i], p)
+				assert.Contains(t, o.Problems[i], "with team values")
+			}
+		})
+	}
+}
+
+func TestVerifyChartFlagsOverrideTeamSettings(t *testing.T) {
+	cmd, o := verifychart.NewCmdVerifyChart()
+	err := cmd.Flags().Parse([]string{"--canary=false", "--hpa"})
+	require.NoError(t, err, "failed to parse flags")
+
+	err = o.DefaultValuesFromTeamSettings(&v1.TeamSettings{
+		DeployKind:    "knative",
+		DeployOptions: &v1.DeployOptions{Canary: true, HPA: false},
+	})
+	require.NoError(t, err, "failed to default from team settings")
+	assert.Equal(t, "knative", o.DeployKind, "o.DeployKind")
+	assert.False(t, o.DeployOptions.Canary, "o.DeployOptions.Canary")
+	assert.True(t, o.DeployOptions.HPA, "o.DeployOptions.HPA")
+}
+
+func TestVerifyChartTeamSettings(t *testing.T) {
+	devEnv := jxenv.CreateDefaultDevEnvironment("jx")
+	devEnv.Namespace = "jx"
+	devEnv.Spec.TeamSettings.DeployKind = "knative"
+	devEnv.Spec.TeamSettings.DeployOptions = &v1.DeployOptions{Canary: true, HPA: true}
+
+	cmd, o := verifychart.NewCmdVerifyChart()
+	err := cmd.Flags().Parse([]string{"--hpa=false"})
+	require.NoError(t, err, "failed to parse flags")
+	o.Dir = filepath.Join("test_data", "myapp")
+	o.Namespace = "jx"
+	o.TeamSettings = true
+	o.KubeClient = fake.NewSimpleClientset()
+	o.JXClient = fakejx.NewSimpleClientset(devEnv)
+
+	err = o.Validate()
+	require.NoError(t, err, "failed to validate")
+	assert.Equal(t, "knative", o.DeployKind, "o.DeployKind")
+	assert.True(t, o.DeployOptions.Canary, "o.DeployOptions.Canary")
+	assert.False(t, o.DeployOptions.HPA, "o.DeployOptions.HPA")
+}
@@ -16,8 +16,27 @@ const (
 	// PlaceHolderDockerRegistryOrg placeholder for docker registry
 	PlaceHolderDockerRegistryOrg = PlaceHolderPrefix + "_DOCKER_REGISTRY_ORG"
 
+	// PlaceHolderDockerRegistry placeholder for the docker registry host
+	PlaceHolderDockerRegistry = PlaceHolderPrefix + "_DOCKER_REGISTRY"
+
+	// PlaceHolderRepoName placeholder for the git repository name
+	PlaceHolderRepoName = PlaceHolderPrefix + "_REPO_NAME"
+
+	// PlaceHolderNamespace placeholder for the namespace of the dev environment
+	PlaceHolderNamespace = PlaceHolderPrefix + "_NAMESPACE"
+
+	// PlaceHolderDefaultBranch placeholder for the default git branch
+	PlaceHolderDefaultBranch = PlaceHolderPrefix + "_DEFAULT_BRANCH"
+
+	// PlaceHolderJavaPackage placeholder for the java package name derived from the org and app name
+	PlaceHolderJavaPackage = PlaceHolderPrefix + "_JAVA_PACKAGE"
+
+	// MinimumMavenDeployVersion the minimum version of the maven-deploy-plugin used by imported projects
 	MinimumMavenDeployVersion = "3.1.4"
 
+	// MinimumMavenSurefireVersion the minimum version of the maven-surefire-plugin used by imported projects
+	MinimumMavenSurefireVersion = "3.5.4"
+
 	// DeployKindKnative for knative serve based deployments
 	DeployKindKnative = "knative"
 
@@ -43,3 +43,16 @@ func DoPackDetectionForBuildPack(out io.Writer, dir, packDir string) (string, er
 	}
 	return "", fmt.Errorf("there was an error detecting the language using packs from %s", packDir)
 }
+
+// DetectLanguages returns the languages detected in the given directory ordered by likelihood
+func DetectLanguages(dir string) ([]*linguist.Language, error) {
+	langs, err := linguist.ProcessDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("there was an error detecting the language: %s", err)
+	}
+	var answer []*linguist.Language
+	for _, lang := range langs {
+		answer = append(answer, linguist.Alias(lang))
+	}
+	return answer, nil
+}
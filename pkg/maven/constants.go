@@ -1,6 +0,0 @@
-package maven
-
-const (
-	// MavenVersion the release version of Apache Maven
-	MavenVersion = "3.5.4"
-)